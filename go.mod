@@ -2,6 +2,9 @@ module github.com/bastienclement/raid-optimizer
 
 go 1.18
 
-require github.com/MaxHalford/eaopt v0.4.2
+require (
+	github.com/MaxHalford/eaopt v0.4.2
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require golang.org/x/sync v0.0.0-20201020160332-67f06af15bc9 // indirect
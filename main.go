@@ -1,165 +1,178 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
-	"math"
-	"math/rand"
 	"os"
 	"os/signal"
 	"runtime/pprof"
 	"time"
 
-	"github.com/MaxHalford/eaopt"
+	"github.com/bastienclement/raid-optimizer/raidopt"
 )
 
-const RMAX = 10
-const CMAX = 100
-
-var strategy Strategy
-var healerMinRatio, healerMaxRatio float64
-var minRaidSize, maxRaidSize int
-
-var roster []Character
-var players []string
-
-var playerCharacters [][]int
-
-type RoleIndex struct {
-	Chars []int
-	Mains []int
-	Alts  []int
-}
-
-var roleIndex = struct {
-	Tank RoleIndex
-	Heal RoleIndex
-	Dps  RoleIndex
-}{}
+var constraints = raidopt.DefaultConstraints
+var config = raidopt.DefaultOptimizerConfig
 
 var minRaids, maxRaids int
-var checkViability bool
-
-var prepareFns []func()
-
-func IndexRoster() {
-	playerCharacters = make([][]int, len(players))
-	for cid, char := range roster {
-		player := char.Player
-		playerCharacters[player] = append(playerCharacters[player], cid)
-
-		var ridx *RoleIndex
-		switch char.Role {
-		case Tank:
-			ridx = &roleIndex.Tank
-		case Healer:
-			ridx = &roleIndex.Heal
-		case Melee, Ranged:
-			ridx = &roleIndex.Dps
-		}
-
-		ridx.Chars = append(ridx.Chars, cid)
-		if char.Main {
-			ridx.Mains = append(ridx.Mains, cid)
-		} else {
-			ridx.Alts = append(ridx.Alts, cid)
-		}
-	}
-}
-
-func ComputeBounds() {
-	// Tanks-related bounds
-	minRaids = Max(minRaids, len(roleIndex.Tank.Mains)/2) // Using only mains
-	maxRaids = Min(maxRaids, len(roleIndex.Tank.Chars)/2) // Using every tanks
-
-	mainCount := float64(len(roleIndex.Tank.Mains) + len(roleIndex.Heal.Mains) + len(roleIndex.Dps.Mains))
-	charCount := float64(len(roleIndex.Tank.Chars) + len(roleIndex.Heal.Chars) + len(roleIndex.Dps.Chars))
-
-	// Roster-related bounds
-	minRaids = Max(minRaids, int(math.Ceil(mainCount/float64(maxRaidSize)))) // Packing mains in the minimum number of raids
-	maxRaids = Min(maxRaids, int(math.Ceil(charCount/float64(minRaidSize)))) // Spreading every char in the smallest possible raids
-
-	// Healers-related bounds
-	// TODO: ensure bounds are not broken when taking healer ratio in consideration
-
-	log.Printf("Raid count bounds: %d-%d", minRaids, maxRaids)
-}
-
-var cpuprofile *string
-
-func ParseOpts(ga *eaopt.GA) {
-	optStrategy := flag.String("strategy", "armor", "optimization strategy")
-
-	flag.IntVar(&minRaidSize, "min-size", 10, "minimum raid size")
-	flag.IntVar(&maxRaidSize, "max-size", 30, "maximum raid size")
+var noCheck bool
+var explain bool
+
+var optStrategy string
+var rosterFormat string
+var rulesFile string
+var previousFile string
+var objectivesFile string
+var movementCost float64
+var outputFormat string
+var cpuprofile string
+var traceFile string
+var byRaidCount bool
+
+func ParseOpts() {
+	flag.StringVar(&optStrategy, "strategy", "armor", "optimization strategy")
+
+	flag.IntVar(&constraints.MinRaidSize, "min-size", constraints.MinRaidSize, "minimum raid size")
+	flag.IntVar(&constraints.MaxRaidSize, "max-size", constraints.MaxRaidSize, "maximum raid size")
 	flag.IntVar(&minRaids, "min", 2, "minimum number of raids")
-	flag.IntVar(&maxRaids, "max", RMAX, "maximum number of raids")
-
-	flag.Float64Var(&healerMinRatio, "healer-min", 0.18, "minimum ratio of healer in raid")
-	flag.Float64Var(&healerMaxRatio, "healer-max", 0.25, "maximum ratio of healer in raid")
-
-	flag.UintVar(&ga.NPops, "npops", 12, "number of populations")
-	flag.UintVar(&ga.PopSize, "popsize", 3000, "number of size of populations")
-	flag.UintVar(&ga.NGenerations, "gen", 2000, "number of generation")
-
-	model := flag.String("model", "default", "the EA model to use")
-	noCheck := flag.Bool("no-check", false, "check raid viability at each steps")
-
-	cpuprofile = flag.String("cpuprofile", "", "write cpu profile to file")
+	flag.IntVar(&maxRaids, "max", 0, "maximum number of raids (0 for no limit)")
+	flag.IntVar(&constraints.MinTanks, "min-tanks", constraints.MinTanks, "minimum number of tanks in raid")
+	flag.IntVar(&constraints.MaxTanks, "max-tanks", constraints.MaxTanks, "maximum number of tanks in raid")
+
+	flag.Float64Var(&constraints.HealerMinRatio, "healer-min", constraints.HealerMinRatio, "minimum ratio of healer in raid")
+	flag.Float64Var(&constraints.HealerMaxRatio, "healer-max", constraints.HealerMaxRatio, "maximum ratio of healer in raid")
+
+	flag.UintVar(&config.NPops, "npops", config.NPops, "number of populations")
+	flag.UintVar(&config.PopSize, "popsize", config.PopSize, "number of size of populations")
+	flag.UintVar(&config.NGenerations, "gen", config.NGenerations, "number of generation")
+
+	flag.StringVar(&rosterFormat, "format", "", "roster format: csv, json or yaml (default from file extension)")
+	flag.StringVar(&rulesFile, "rules", "", "rules file with pinned, grouped, separated and benched characters")
+	flag.StringVar(&previousFile, "previous", "", "previous split (json or csv output) to start from")
+	flag.StringVar(&objectivesFile, "objectives", "", "objectives file with the fitness weights and raid buffs")
+	flag.Float64Var(&movementCost, "move-cost", -1, "fitness penalty for each character moved from its previous raid (overrides the movement weight)")
+	flag.StringVar(&outputFormat, "output", "text", "output format of the best split: text, json or csv")
+	flag.BoolVar(&byRaidCount, "by-raid-count", false, "output the best split of each raid count instead of the overall best")
+	flag.UintVar(&config.TopK, "top-k", config.TopK, "output this number of diverse splits instead of the best one (0 to disable)")
+	flag.IntVar(&config.MinDistance, "min-distance", config.MinDistance, "minimum distance between diverse splits, in character pairs playing together in only one of them")
+
+	flag.StringVar(&config.Model, "model", config.Model, "the EA model to use: default, mutonly, mutonly-nonstrict or nsga2 (Pareto front of the objectives)")
+	flag.BoolVar(&noCheck, "no-check", false, "check raid viability at each steps")
+	flag.BoolVar(&explain, "explain", false, "explain why the roster cannot be split into each raid count, then exit")
+
+	flag.StringVar(&cpuprofile, "cpuprofile", "", "write cpu profile to file")
+	flag.StringVar(&traceFile, "trace", "", "write the statistics of every population after each generation to a csv file")
 	flag.Parse()
 
-	strategy = ParseStrategy(*optStrategy)
-	checkViability = !*noCheck
+	if err := constraints.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := raidopt.ParseModel(config.Model); err != nil {
+		log.Fatal(err)
+	}
 
-	switch *model {
-	case "mutonly":
-		ga.Model = eaopt.ModMutationOnly{Strict: true}
-	case "mutonly-nonstrict":
-		ga.Model = eaopt.ModMutationOnly{Strict: false}
-	case "default":
-		ga.Model = eaopt.ModGenerational{Selector: eaopt.SelTournament{NContestants: 3}, MutRate: 1, CrossRate: 1}
+	switch outputFormat {
+	case "text", "json", "csv":
 	default:
-		log.Fatalf("Unknown model: %s", *model)
+		log.Fatalf("Unknown output format: %s", outputFormat)
 	}
 }
 
 func main() {
 	log.SetFlags(0)
-	ga, err := eaopt.NewDefaultGAConfig().NewGA()
-	if err != nil {
-		log.Fatal(err)
+
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		Serve(os.Args[2:])
+		return
 	}
 
-	ParseOpts(ga)
+	ParseOpts()
 	if flag.NArg() < 1 {
 		flag.Usage()
 		return
 	}
 
+	strategy, err := raidopt.ParseStrategy(optStrategy, flag.Args()[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	log.Printf("Using strategy: %s", strategy)
-	log.Printf("Checking viability: %v\n", checkViability)
-	log.Printf("Model: %+v\n\n", ga.Model)
+	log.Printf("Checking viability: %v", !noCheck)
+	log.Printf("Constraints: %+v\n", constraints)
+	log.Printf("Model: %s\n\n", config.Model)
 
 	log.Printf("Loading roster...")
-	roster, players = LoadRoster()
+	roster, players, err := raidopt.LoadRoster(flag.Arg(0), rosterFormat, strategy)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	log.Printf("Indexing roster...")
-	IndexRoster()
-	ComputeBounds()
-	fmt.Fprint(os.Stderr, "\n")
+	problem := raidopt.NewProblem(roster, players, strategy)
+	problem.Constraints = constraints
+	problem.MinRaids, problem.MaxRaids = minRaids, maxRaids
+	problem.CheckViability = !noCheck
+	problem.Logger = log.Default()
+
+	if rulesFile != "" {
+		rules, err := raidopt.LoadRules(rulesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := problem.SetRules(rules); err != nil {
+			log.Fatalf("%s: %s", rulesFile, err)
+		}
+	}
 
-	strategy.Prepare()
-	for _, fn := range prepareFns {
-		fn()
+	if objectivesFile != "" {
+		if problem.Objectives, err = raidopt.LoadObjectives(objectivesFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if movementCost >= 0 {
+		if problem.Objectives.Weights == nil {
+			problem.Objectives.Weights = make(map[string]float64)
+		}
+		problem.Objectives.Weights["movement"] = movementCost
+	}
+	if err := problem.Objectives.Validate(strategy); err != nil {
+		if objectivesFile != "" {
+			log.Fatalf("%s: %s", objectivesFile, err)
+		}
+		log.Fatalf("objectives: %s", err)
+	}
+
+	if previousFile != "" {
+		previous, err := raidopt.LoadSplit(previousFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := problem.SetPrevious(previous); err != nil {
+			log.Fatalf("%s: %s", previousFile, err)
+		}
 	}
 
+	if explain {
+		problem.Explain(os.Stdout)
+		return
+	}
+
+	if err := problem.Prepare(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprint(os.Stderr, "\n")
+
+	optimizer := raidopt.NewOptimizer(problem, config)
+
 	var nextPercent uint = 0
-	var stop bool = false
 	var start int64
 
-	ga.Callback = func(ga *eaopt.GA) {
-		percent := ga.Generations * 100 / ga.NGenerations
+	optimizer.OnProgress = func(progress raidopt.Progress) {
+		percent := progress.Generation * 100 / progress.Generations
 		if percent >= nextPercent {
 			nextPercent = percent + 5
 			var eta string
@@ -170,40 +183,37 @@ func main() {
 				start = time.Now().UnixMilli()
 			}
 
-			log.Printf("Best fitness after %3d%%: %f%s", percent, ga.HallOfFame[0].Fitness, eta)
+			log.Printf("Best fitness after %3d%%: %f%s", percent, progress.Best, eta)
 		}
 	}
 
-	ga.EarlyStop = func(ga *eaopt.GA) bool {
-		return stop
-	}
-
+	// Stop gracefully on the first interrupt, exit on the second one
+	ctx, stop := context.WithCancel(context.Background())
 	go func() {
 		c := make(chan os.Signal, 1)
 		signal.Notify(c, os.Interrupt)
-		for {
-			<-c
-			if stop {
-				os.Exit(1)
-			}
-			stop = true
-		}
-		//log.Printf("Interrupted")
+		<-c
+		stop()
+		<-c
+		os.Exit(1)
 	}()
 
-	ga.MigFrequency = ga.NGenerations / 5
-	ga.Migrator = eaopt.MigRing{NMigrants: ga.PopSize / 4}
-	ga.HofSize = 1
-
-	if minRaids != maxRaids {
-		ga.Speciator = Speciator{}
+	if traceFile != "" {
+		f, err := os.Create(traceFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		trace := raidopt.NewTraceWriter(f)
+		optimizer.OnGeneration = func(stats []raidopt.PopulationStats) {
+			if err := trace.Write(stats); err != nil {
+				log.Fatalf("%s: %s", traceFile, err)
+			}
+		}
 	}
 
-	//rng.Seed(time.Now().UnixNano())
-	//ga.RNG = rand.New(&rng)
-
-	if *cpuprofile != "" {
-		f, err := os.Create(*cpuprofile)
+	if cpuprofile != "" {
+		f, err := os.Create(cpuprofile)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -212,17 +222,35 @@ func main() {
 	}
 
 	fmt.Fprint(os.Stderr, "\n")
-	log.Printf("Starting...")
-	err = ga.Minimize(func(rng *rand.Rand) eaopt.Genome {
-		return MakeRaid(rng)
-	})
-
+	best, err := optimizer.Run(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	fmt.Fprintf(os.Stderr, "\n")
-	for i := 0; i < int(ga.HofSize); i++ {
-		PrintRaid(ga.HallOfFame[i].Genome.(*Genome))
+	raidopt.PrintOperatorStats(os.Stderr, problem.OperatorStats())
+	fmt.Fprintf(os.Stderr, "\n")
+	if byRaidCount {
+		if err := raidopt.WriteByRaidCount(os.Stdout, optimizer.BestByRaidCount(), outputFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if config.Model == "nsga2" {
+		if err := raidopt.WriteFront(os.Stdout, best, outputFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if config.TopK > 0 {
+		if err := raidopt.WriteDiverse(os.Stdout, best, outputFormat); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	for _, X := range best {
+		if err := raidopt.WriteSplit(os.Stdout, X, outputFormat); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
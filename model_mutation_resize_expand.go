@@ -1,13 +0,0 @@
-package main
-
-import (
-	"math/rand"
-)
-
-func (X *Genome) MutResizeExpand(rng *rand.Rand) {
-	if X.RaidCount == maxRaids {
-		return
-	}
-
-	// TODO: implement
-}
@@ -0,0 +1,69 @@
+package raidopt
+
+import (
+	"sort"
+)
+
+// Best individuals of each population offered to the diverse archive after each generation
+const diverseCandidates = 100
+
+// Keeps the best genomes found that are all at least a minimum distance apart, best first
+type diverseArchive struct {
+	size        int
+	minDistance int
+	genomes     []*Genome
+	fitness     []float64
+}
+
+func newDiverseArchive(size int, minDistance int) *diverseArchive {
+	return &diverseArchive{size: size, minDistance: Max(minDistance, 1)}
+}
+
+// Adds a genome to the archive if it is better than the worst one, replacing the genomes too close to it if it is
+// better than all of them
+func (a *diverseArchive) offer(X *Genome, fitness float64) {
+	if len(a.genomes) == a.size && fitness >= a.fitness[len(a.genomes)-1] {
+		return
+	}
+
+	var close []int
+	for i, Y := range a.genomes {
+		if X.Distance(Y) < a.minDistance {
+			if a.fitness[i] <= fitness {
+				return // A genome as good is already kept
+			}
+			close = append(close, i)
+		}
+	}
+
+	genomes, fitnesses := a.genomes[:0], a.fitness[:0]
+	for i, Y := range a.genomes {
+		if len(close) > 0 && close[0] == i {
+			close = close[1:]
+			continue
+		}
+		genomes, fitnesses = append(genomes, Y), append(fitnesses, a.fitness[i])
+	}
+
+	i := sort.SearchFloat64s(fitnesses, fitness)
+	genomes = append(genomes[:i], append([]*Genome{X.Clone().(*Genome)}, genomes[i:]...)...)
+	fitnesses = append(fitnesses[:i], append([]float64{fitness}, fitnesses[i:]...)...)
+	if len(genomes) > a.size {
+		genomes, fitnesses = genomes[:a.size], fitnesses[:a.size]
+	}
+	a.genomes, a.fitness = genomes, fitnesses
+}
+
+// Offers the best genomes of a population to the archive
+func (a *diverseArchive) offerPopulation(genomes []*Genome, fitness []float64) {
+	order := make([]int, len(genomes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return fitness[order[i]] < fitness[order[j]]
+	})
+	for _, i := range order[:Min(len(order), diverseCandidates)] {
+		a.offer(genomes[i], fitness[i])
+	}
+}
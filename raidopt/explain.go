@@ -0,0 +1,80 @@
+package raidopt
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+)
+
+const explainAttempts = 1000
+
+// Explain attempts to build a viable distribution for every requested raid count and writes the constraints
+// preventing it when none can be found.
+func (p *Problem) Explain(w io.Writer) {
+	p.ComputeBounds()
+	from, to := p.requestedRaids()
+	if p.MaxRaids <= 0 {
+		to = p.maxRaids // No explicit limit, only the raid counts the roster can support are explained
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for raidCount := from; raidCount <= to; raidCount++ {
+		fmt.Fprintf(w, "%d raids", raidCount)
+		if raidCount < p.minRaids || raidCount > p.maxRaids {
+			fmt.Fprintf(w, " (outside roster bounds %d-%d)", p.minRaids, p.maxRaids)
+		}
+
+		if reasons := p.InfeasibilityReasons(raidCount); len(reasons) > 0 {
+			fmt.Fprintf(w, ": infeasible\n")
+			for _, reason := range reasons {
+				fmt.Fprintf(w, "  - %s\n", reason)
+			}
+			fmt.Fprintf(w, "\n")
+			continue
+		}
+
+		var closest []Violation
+		var failures [constraintCount]int
+
+		attempt := 0
+		for ; attempt < explainAttempts; attempt++ {
+			violations := p.TryMakeRaid(rng, raidCount).Violations()
+			if len(violations) == 0 {
+				break
+			}
+
+			// Count attempts failing on each constraint
+			var failed [constraintCount]bool
+			for _, v := range violations {
+				failed[v.Constraint] = true
+			}
+			for c, f := range failed {
+				if f {
+					failures[c] += 1
+				}
+			}
+
+			if closest == nil || len(violations) < len(closest) {
+				closest = violations
+			}
+		}
+
+		if attempt < explainAttempts {
+			fmt.Fprintf(w, ": viable split found after %d attempts\n\n", attempt+1)
+			continue
+		}
+
+		fmt.Fprintf(w, ": no viable split found in %d attempts\n", explainAttempts)
+		for c := ConstraintBenchedMain; c < constraintCount; c++ {
+			if failures[c] > 0 {
+				fmt.Fprintf(w, "  %-16s violated in %3d%% of attempts\n", c, failures[c]*100/explainAttempts)
+			}
+		}
+		fmt.Fprintf(w, "  Closest attempt:\n")
+		for _, v := range closest {
+			fmt.Fprintf(w, "    %s\n", v)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+}
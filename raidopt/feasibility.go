@@ -0,0 +1,171 @@
+package raidopt
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const feasibilityAttempts = 1000
+
+// Counts, for a given raid count, how many characters matching the filter can actually be spotted, taking into
+// account that a player cannot play more than one character per raid.
+func (p *Problem) spottable(raidCount int, filter func(char Character) bool) int {
+	total := 0
+	for player, chars := range p.playerCharacters {
+		count := 0
+		for _, cid := range chars {
+			if filter(p.Roster[cid]) {
+				count += 1
+			}
+		}
+		total += Min(count, p.availableRaids(player, raidCount))
+	}
+	return total
+}
+
+// InfeasibilityReasons returns the necessary conditions that the roster fails to meet for the given raid count.
+// An empty result does not prove that the raid count is feasible.
+func (p *Problem) InfeasibilityReasons(raidCount int) (reasons []string) {
+	constraints := p.Constraints
+	every := func(char Character) bool { return true }
+	isTank := func(char Character) bool { return char.HasRole(Tank) }
+	isHealer := func(char Character) bool { return char.HasRole(Healer) }
+
+	// Mains
+	mainCount := len(p.roleIndex.Tank.Mains) + len(p.roleIndex.Heal.Mains) + len(p.roleIndex.Dps.Mains)
+	if mainCount > raidCount*constraints.MaxRaidSize {
+		reasons = append(reasons, fmt.Sprintf("%d mains cannot fit in %d raids of at most %d characters, raise -max-size",
+			mainCount, raidCount, constraints.MaxRaidSize))
+	}
+	for player, chars := range p.playerCharacters {
+		mains := 0
+		for _, cid := range chars {
+			if p.Roster[cid].Main {
+				mains += 1
+			}
+		}
+		if mains > raidCount {
+			reasons = append(reasons, fmt.Sprintf("player %s has %d mains but a player can only play once per raid",
+				p.Players[player], mains))
+		} else if available := p.availableRaids(player, raidCount); mains > available {
+			reasons = append(reasons, fmt.Sprintf("player %s has %d mains but can only attend %d of the raids",
+				p.Players[player], mains, available))
+		}
+	}
+
+	// Raid size
+	if chars := p.spottable(raidCount, every); chars < raidCount*constraints.MinRaidSize {
+		reasons = append(reasons, fmt.Sprintf("only %d characters can be spotted, %d raids of at least %d characters need %d, lower -min-size",
+			chars, raidCount, constraints.MinRaidSize, raidCount*constraints.MinRaidSize))
+	}
+
+	// Tanks
+	if mainTanks := len(p.roleIndex.Tank.Fixed); mainTanks > raidCount*constraints.MaxTanks {
+		reasons = append(reasons, fmt.Sprintf("%d main tanks cannot fit in %d raids of at most %d tanks, raise -max-tanks",
+			mainTanks, raidCount, constraints.MaxTanks))
+	}
+	if tanks := p.spottable(raidCount, isTank); tanks < raidCount*constraints.MinTanks {
+		reasons = append(reasons, fmt.Sprintf("only %d tanks can be spotted, %d raids of at least %d tanks need %d, lower -min-tanks",
+			tanks, raidCount, constraints.MinTanks, raidCount*constraints.MinTanks))
+	}
+
+	// Healers
+	healers := p.spottable(raidCount, isHealer)
+	minHealers := int(math.Ceil(constraints.HealerMinRatio * float64(constraints.MinRaidSize)))
+	if healers < raidCount*minHealers {
+		reasons = append(reasons, fmt.Sprintf("only %d healers can be spotted, %d raids of at least %d healers need %d, lower -healer-min",
+			healers, raidCount, minHealers, raidCount*minHealers))
+	}
+	maxHealers := int(math.Floor(constraints.HealerMaxRatio * float64(constraints.MaxRaidSize)))
+	if mainHealers := len(p.roleIndex.Heal.Fixed); mainHealers > raidCount*maxHealers {
+		reasons = append(reasons, fmt.Sprintf("%d main healers cannot fit in %d raids of at most %d healers, raise -healer-max",
+			mainHealers, raidCount, maxHealers))
+	}
+	if supported := int(math.Floor(float64(healers) / constraints.HealerMinRatio)); mainCount > supported {
+		reasons = append(reasons, fmt.Sprintf("%d spottable healers can only support %d characters but there are %d mains, lower -healer-min",
+			healers, supported, mainCount))
+	}
+	if required := int(math.Ceil(float64(len(p.roleIndex.Heal.Fixed)) / constraints.HealerMaxRatio)); p.spottable(raidCount, every) < required {
+		reasons = append(reasons, fmt.Sprintf("%d main healers require at least %d characters to keep the healer ratio, raise -healer-max",
+			len(p.roleIndex.Heal.Fixed), required))
+	}
+
+	// Rules
+	if names := len(p.rules.raidNames); names > 0 && raidCount > names {
+		reasons = append(reasons, fmt.Sprintf("only %d raids are named", names))
+	}
+	for cid, pin := range p.rules.pins {
+		if pin < 0 {
+			continue
+		}
+		name := p.Roster[cid].Name
+		if pin >= raidCount {
+			reasons = append(reasons, fmt.Sprintf("%s is pinned to raid %d", name, pin+1))
+		}
+		for oid := cid + 1; oid < len(p.Roster); oid++ {
+			if p.rules.pins[oid] == pin && p.Roster[oid].Player == p.Roster[cid].Player {
+				reasons = append(reasons, fmt.Sprintf("%s and %s are pinned to raid %d but belong to the same player",
+					name, p.Roster[oid].Name, pin+1))
+			}
+		}
+		for _, oid := range p.rules.rivals[cid] {
+			if oid > cid && p.rules.pins[oid] == pin {
+				reasons = append(reasons, fmt.Sprintf("%s and %s are pinned to raid %d but must play apart",
+					name, p.Roster[oid].Name, pin+1))
+			}
+		}
+		for _, oid := range p.rules.mates[cid] {
+			if oid > cid && p.rules.pins[oid] >= 0 && p.rules.pins[oid] != pin {
+				reasons = append(reasons, fmt.Sprintf("%s and %s must play together but are pinned to raids %d and %d",
+					name, p.Roster[oid].Name, pin+1, p.rules.pins[oid]+1))
+			}
+		}
+	}
+
+	return
+}
+
+// CheckFeasibility proves or disproves that each raid count in the current bounds admits a viable distribution,
+// then narrows the bounds to the feasible raid counts. Fails if none of them is feasible.
+func (p *Problem) CheckFeasibility() error {
+	p.logf("Checking feasibility...")
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	p.raidCounts = p.raidCounts[:0]
+	for raidCount := p.minRaids; raidCount <= p.maxRaids; raidCount++ {
+		if reasons := p.InfeasibilityReasons(raidCount); len(reasons) > 0 {
+			p.logf("  %d raids: infeasible", raidCount)
+			for _, reason := range reasons {
+				p.logf("    - %s", reason)
+			}
+			continue
+		}
+
+		// Necessary conditions are met, attempt to find an actual viable distribution
+		for attempt := 0; attempt < feasibilityAttempts; attempt++ {
+			if X := p.TryMakeRaid(rng, raidCount); X.repair(rng) {
+				p.witnesses[raidCount] = X
+				break
+			}
+		}
+
+		if p.witnesses[raidCount] == nil {
+			p.logf("  %d raids: no viable split found in %d attempts, run with -explain for details", raidCount, feasibilityAttempts)
+			continue
+		}
+
+		p.logf("  %d raids: feasible", raidCount)
+		p.raidCounts = append(p.raidCounts, raidCount)
+	}
+
+	if len(p.raidCounts) == 0 {
+		return fmt.Errorf("no feasible raid count in %d-%d, adjust the constraints or the roster and run with -explain for details",
+			p.minRaids, p.maxRaids)
+	}
+
+	p.minRaids, p.maxRaids = p.raidCounts[0], p.raidCounts[len(p.raidCounts)-1]
+	p.logf("Feasible raid counts: %v", p.raidCounts)
+	return nil
+}
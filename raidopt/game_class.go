@@ -1,7 +1,8 @@
-package main
+package raidopt
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Class int
@@ -38,22 +39,37 @@ var classes = map[string]Class{
 	"demonhunter": DemonHunter,
 }
 
+// Canonical class names, as produced by String
+var classNames = [...]string{
+	Warrior:     "warrior",
+	Paladin:     "paladin",
+	Hunter:      "hunter",
+	Rogue:       "rogue",
+	Priest:      "priest",
+	DeathKnight: "deathknight",
+	Shaman:      "shaman",
+	Mage:        "mage",
+	Warlock:     "warlock",
+	Monk:        "monk",
+	Druid:       "druid",
+	DemonHunter: "demonhunter",
+}
+
 func (cls Class) String() string {
-	for str, c := range classes {
-		if cls == c {
-			return str
-		}
+	if cls >= Warrior && int(cls) < len(classNames) {
+		return classNames[cls]
 	}
 
 	return fmt.Sprintf("<Class %d>", cls)
 }
 
-func ParseClass(str string) Class {
+func ParseClass(str string) (Class, error) {
+	str = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(str)))
 	if cls, found := classes[str]; found {
-		return cls
+		return cls, nil
 	}
 
-	panic("Unknown class: " + str)
+	return 0, fmt.Errorf("unknown class: %q", str)
 }
 
 func ClassColor(class Class) (int, int, int) {
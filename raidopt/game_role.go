@@ -1,4 +1,4 @@
-package main
+package raidopt
 
 import (
 	"fmt"
@@ -29,18 +29,18 @@ func (r Role) String() string {
 	return fmt.Sprintf("<Role %d>", r)
 }
 
-func ParseRole(str string) Role {
-	switch strings.ToLower(str) {
+func ParseRole(str string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
 	case "tank":
-		return Tank
-	case "healer":
-		return Healer
+		return Tank, nil
+	case "healer", "heal":
+		return Healer, nil
 	case "melee":
-		return Melee
+		return Melee, nil
 	case "ranged":
-		return Ranged
+		return Ranged, nil
 	default:
-		panic(fmt.Sprintf("Unknown role: %s", str))
+		return 0, fmt.Errorf("unknown role: %q", str)
 	}
 }
 
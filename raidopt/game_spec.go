@@ -1,7 +1,8 @@
-package main
+package raidopt
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Specialization uint64
@@ -46,9 +47,9 @@ const (
 )
 
 var specs = map[Class]map[string]Specialization{
-	Warrior:     {"arm": WarriorArm, "fury": WarriorFury, "protection": WarriorProtection},
+	Warrior:     {"arms": WarriorArm, "fury": WarriorFury, "protection": WarriorProtection},
 	Paladin:     {"holy": PaladinHoly, "protection": PaladinProtection, "retribution": PaladinRetribution},
-	Hunter:      {"beastmaster": HunterBeastMaster, "marksmanship": HunterMarksmanship, "survival": HunterSurvival},
+	Hunter:      {"beastmastery": HunterBeastMaster, "marksmanship": HunterMarksmanship, "survival": HunterSurvival},
 	Rogue:       {"assassination": RogueAssassination, "subtlety": RogueSubtlety, "outlaw": RogueOutlaw},
 	Priest:      {"discipline": PriestDiscipline, "holy": PriestHoly, "shadow": PriestShadow},
 	DeathKnight: {"blood": DeathKnightBlood, "frost": DeathKnightFrost, "unholy": DeathKnightUnholy},
@@ -60,6 +61,12 @@ var specs = map[Class]map[string]Specialization{
 	DemonHunter: {"havoc": DemonHunterHavoc, "vengeance": DemonHunterVengeance},
 }
 
+// Former spec names, still accepted when parsing
+var specAliases = map[Class]map[string]string{
+	Warrior: {"arm": "arms"},
+	Hunter:  {"beastmaster": "beastmastery"},
+}
+
 func (spec Specialization) String() string {
 	for _, clsSpecs := range specs {
 		for str, s := range clsSpecs {
@@ -72,10 +79,14 @@ func (spec Specialization) String() string {
 	return fmt.Sprintf("<Spec %d>", spec)
 }
 
-func ParseSpec(cls Class, str string) Specialization {
+func ParseSpec(cls Class, str string) (Specialization, error) {
+	str = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(str)))
+	if alias, found := specAliases[cls][str]; found {
+		str = alias
+	}
 	if spec, found := specs[cls][str]; found {
-		return spec
+		return spec, nil
 	}
 
-	panic(fmt.Sprintf("Unknown class/spec: %s/%s", cls, str))
+	return 0, fmt.Errorf("unknown spec for class %s: %q", cls, str)
 }
@@ -1,4 +1,4 @@
-package main
+package raidopt
 
 import (
 	"fmt"
@@ -126,16 +126,16 @@ func (tss TokenSlotSet) String() (str string) {
 	return
 }
 
-func ParseTokenSlots(str string) (set TokenSlotSet) {
+func ParseTokenSlots(str string) (set TokenSlotSet, err error) {
 	if str != "" {
 		for _, s := range strings.Split(str, "/") {
-			s = strings.ToLower(s)
+			s = strings.ToLower(strings.TrimSpace(s))
 			if slot, found := slots[s]; found {
 				set.Set(slot)
 				continue
 			}
 
-			panic(fmt.Sprintf("Unknown slot: %s", s))
+			return 0, fmt.Errorf("unknown slot: %q", s)
 		}
 	}
 	return
@@ -0,0 +1,52 @@
+package raidopt
+
+// FitnessTerm is the value of an objective for a genome, along with its weight
+type FitnessTerm struct {
+	Name   string
+	Value  float64
+	Weight float64
+}
+
+// Contribution returns the share of the term in the total fitness
+func (t FitnessTerm) Contribution() float64 {
+	return t.Value * t.Weight
+}
+
+// FitnessBreakdown is the value of every weighted objective of a genome
+type FitnessBreakdown []FitnessTerm
+
+// Total returns the fitness minimized by the genetic algorithm
+func (fb FitnessBreakdown) Total() float64 {
+	var total float64
+	for _, t := range fb {
+		total += t.Contribution()
+	}
+	return total
+}
+
+func (X *Genome) Evaluate() (float64, error) {
+	var total float64
+	for _, o := range X.problem.objectives {
+		total += o.Evaluate(X) * o.weight
+	}
+	X.setFitness(total)
+	return total, nil
+}
+
+// Records the fitness of the genome, rewarding the operator of its last mutation if any
+func (X *Genome) setFitness(fitness float64) {
+	if X.pending != nil {
+		X.pending.reward(fitness)
+		X.pending = nil
+	}
+	X.fitness, X.evaluated = fitness, true
+}
+
+// Fitness computes every fitness objective of the genome
+func (X *Genome) Fitness() FitnessBreakdown {
+	fb := make(FitnessBreakdown, len(X.problem.objectives))
+	for i, o := range X.problem.objectives {
+		fb[i] = FitnessTerm{Name: o.Name, Value: o.Evaluate(X), Weight: o.weight}
+	}
+	return fb
+}
@@ -0,0 +1,186 @@
+package raidopt
+
+import (
+	"fmt"
+	"math"
+)
+
+// Constraint identifies one of the rules a distribution must follow to be viable
+type Constraint int
+
+const (
+	ConstraintNone Constraint = iota
+	ConstraintBenchedMain
+	ConstraintDuplicatePlayer
+	ConstraintRaidSize
+	ConstraintTankCount
+	ConstraintHealerRatio
+	ConstraintRole
+	ConstraintPin
+	ConstraintTogether
+	ConstraintApart
+	ConstraintBench
+	ConstraintAvailability
+
+	constraintCount = iota
+)
+
+func (c Constraint) String() string {
+	switch c {
+	case ConstraintNone:
+		return "None"
+	case ConstraintBenchedMain:
+		return "Benched main"
+	case ConstraintDuplicatePlayer:
+		return "Duplicate player"
+	case ConstraintRaidSize:
+		return "Raid size"
+	case ConstraintTankCount:
+		return "Tank count"
+	case ConstraintHealerRatio:
+		return "Healer ratio"
+	case ConstraintRole:
+		return "Role"
+	case ConstraintPin:
+		return "Pin"
+	case ConstraintTogether:
+		return "Together"
+	case ConstraintApart:
+		return "Apart"
+	case ConstraintBench:
+		return "Bench"
+	case ConstraintAvailability:
+		return "Availability"
+	}
+
+	return fmt.Sprintf("<Constraint %d>", c)
+}
+
+// Constraints holds the bounds every raid must respect. This is the single source of truth used by the
+// initializer, the mutations and the viability check.
+type Constraints struct {
+	MinRaidSize    int     `json:"min_size"`
+	MaxRaidSize    int     `json:"max_size"`
+	MinTanks       int     `json:"min_tanks"`
+	MaxTanks       int     `json:"max_tanks"`
+	HealerMinRatio float64 `json:"healer_min"`
+	HealerMaxRatio float64 `json:"healer_max"`
+}
+
+var DefaultConstraints = Constraints{
+	MinRaidSize:    10,
+	MaxRaidSize:    30,
+	MinTanks:       2,
+	MaxTanks:       2,
+	HealerMinRatio: 0.18,
+	HealerMaxRatio: 0.25,
+}
+
+// Validate checks that the bounds are consistent
+func (cs Constraints) Validate() error {
+	if cs.MinRaidSize < 1 || cs.MaxRaidSize < cs.MinRaidSize {
+		return fmt.Errorf("invalid raid size bounds: %d-%d", cs.MinRaidSize, cs.MaxRaidSize)
+	}
+	if cs.MinTanks < 0 || cs.MaxTanks < cs.MinTanks {
+		return fmt.Errorf("invalid tank bounds: %d-%d", cs.MinTanks, cs.MaxTanks)
+	}
+	if cs.HealerMinRatio <= 0 || cs.HealerMaxRatio < cs.HealerMinRatio {
+		return fmt.Errorf("invalid healer ratio bounds: %g-%g", cs.HealerMinRatio, cs.HealerMaxRatio)
+	}
+	return nil
+}
+
+// RaidComp is the composition of a raid, as far as constraints are concerned
+type RaidComp struct {
+	Count   int
+	Tanks   int
+	Healers int
+}
+
+func (rc *RaidComp) Add(role Role) {
+	rc.Count += 1
+	switch role {
+	case Tank:
+		rc.Tanks += 1
+	case Healer:
+		rc.Healers += 1
+	}
+}
+
+func (rc *RaidComp) Remove(role Role) {
+	rc.Count -= 1
+	switch role {
+	case Tank:
+		rc.Tanks -= 1
+	case Healer:
+		rc.Healers -= 1
+	}
+}
+
+// With returns the composition of the raid after adding a character with the given role
+func (rc RaidComp) With(role Role) RaidComp {
+	rc.Add(role)
+	return rc
+}
+
+// Without returns the composition of the raid after removing a character with the given role
+func (rc RaidComp) Without(role Role) RaidComp {
+	rc.Remove(role)
+	return rc
+}
+
+// Replace returns the composition of the raid after replacing a character having the role `from` with one having
+// the role `to`
+func (rc RaidComp) Replace(from Role, to Role) RaidComp {
+	rc.Remove(from)
+	rc.Add(to)
+	return rc
+}
+
+func (rc RaidComp) HealerRatio() float64 {
+	return float64(rc.Healers) / float64(rc.Count)
+}
+
+func (cs Constraints) SizeOk(rc RaidComp) bool {
+	return rc.Count >= cs.MinRaidSize && rc.Count <= cs.MaxRaidSize
+}
+
+func (cs Constraints) TanksOk(rc RaidComp) bool {
+	return rc.Tanks >= cs.MinTanks && rc.Tanks <= cs.MaxTanks
+}
+
+func (cs Constraints) HealerRatioOk(rc RaidComp) bool {
+	ratio := rc.HealerRatio()
+	return ratio >= cs.HealerMinRatio && ratio <= cs.HealerMaxRatio
+}
+
+// Check returns the first raid-level constraint broken by the composition, or ConstraintNone
+func (cs Constraints) Check(rc RaidComp) Constraint {
+	if !cs.SizeOk(rc) {
+		return ConstraintRaidSize
+	}
+	if !cs.TanksOk(rc) {
+		return ConstraintTankCount
+	}
+	if !cs.HealerRatioOk(rc) {
+		return ConstraintHealerRatio
+	}
+	return ConstraintNone
+}
+
+// Distance returns how many characters the raid lacks or has in excess to respect the constraints, 0 if it respects
+// them
+func (cs Constraints) Distance(rc RaidComp) int {
+	distance := Max(cs.MinRaidSize-rc.Count, 0) + Max(rc.Count-cs.MaxRaidSize, 0)
+	distance += Max(cs.MinTanks-rc.Tanks, 0) + Max(rc.Tanks-cs.MaxTanks, 0)
+
+	minHealers := int(math.Ceil(cs.HealerMinRatio * float64(rc.Count)))
+	maxHealers := int(math.Floor(cs.HealerMaxRatio * float64(rc.Count)))
+	distance += Max(minHealers-rc.Healers, 0) + Max(rc.Healers-maxHealers, 0)
+	return distance
+}
+
+// ExtraCapacity returns how many non-healer characters can be added to the raid without breaking its constraints
+func (cs Constraints) ExtraCapacity(rc RaidComp) int {
+	return Min(int(math.Floor(float64(rc.Healers)/cs.HealerMinRatio))-rc.Count, cs.MaxRaidSize-rc.Count)
+}
@@ -0,0 +1,57 @@
+package raidopt
+
+import (
+	"math/rand"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// Crossover makes each parent inherit one raid of the other parent, as is. The characters of the inherited raid leave
+// their former raid, and the characters it replaces are benched, then the result is repaired. Parents having different
+// raid counts, or children that cannot be repaired, are mutated instead.
+func (X *Genome) Crossover(Y eaopt.Genome, rng *rand.Rand) {
+	Z := Y.(*Genome)
+	if X.RaidCount != Z.RaidCount {
+		X.Mutate(rng)
+		Z.Mutate(rng)
+		return
+	}
+
+	x := X.Clone().(*Genome)
+	if !x.inheritRaid(Z, rng.Intn(X.RaidCount), rng) {
+		x = nil
+	}
+	if !Z.inheritRaid(X, rng.Intn(X.RaidCount), rng) {
+		Z.Mutate(rng)
+	}
+	if x != nil {
+		copy(X.Distribution, x.Distribution)
+		copy(X.Roles, x.Roles)
+		X.evaluated = false
+	} else {
+		X.Mutate(rng)
+	}
+}
+
+// Replaces the raid of the genome with the one of the other genome. Returns false, leaving the genome unchanged, if
+// the result cannot be repaired.
+func (X *Genome) inheritRaid(from *Genome, rid int, rng *rand.Rand) bool {
+	Y := X.Clone().(*Genome)
+
+	// Characters joining the raid leave their former one, characters not in the inherited raid are benched
+	for cid, orid := range from.Distribution {
+		if orid == rid {
+			Y.Distribution[cid], Y.Roles[cid] = rid, from.Roles[cid]
+		} else if Y.Distribution[cid] == rid {
+			Y.Distribution[cid] = -1
+		}
+	}
+
+	if !Y.Repair(rng) {
+		return false
+	}
+	copy(X.Distribution, Y.Distribution)
+	copy(X.Roles, Y.Roles)
+	X.evaluated = false
+	return true
+}
@@ -0,0 +1,99 @@
+package raidopt
+
+import (
+	"github.com/MaxHalford/eaopt"
+)
+
+type Genome struct {
+	problem *Problem
+
+	RaidCount    int
+	Distribution []int
+	Roles        []Role // Role assigned to each character, only meaningful if the character is in a raid
+
+	fitness   float64          // Fitness when last evaluated
+	evaluated bool             // Whether fitness is the one of the current distribution
+	pending   *pendingMutation // Last mutation, rewarded when the genome is evaluated
+}
+
+func (X *Genome) Clone() eaopt.Genome {
+	Y := Genome{
+		problem:      X.problem,
+		RaidCount:    X.RaidCount,
+		Distribution: make([]int, len(X.Distribution)),
+		Roles:        make([]Role, len(X.Roles)),
+		fitness:      X.fitness,
+		evaluated:    X.evaluated,
+	}
+	copy(Y.Distribution, X.Distribution)
+	copy(Y.Roles, X.Roles)
+	return &Y
+}
+
+// canSwapRaids returns whether the rules allow the characters of both raids to trade their raid number
+func (X *Genome) canSwapRaids(a, b int) bool {
+	p := X.problem
+	for cid, rid := range X.Distribution {
+		if rid != a && rid != b {
+			continue
+		}
+		target := a + b - rid
+		if p.pinned(cid) || !p.Available(p.Roster[cid].Player, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// swapRaids exchanges the raid numbers of two raids
+func (X *Genome) swapRaids(a, b int) {
+	for cid, rid := range X.Distribution {
+		if rid == a {
+			X.Distribution[cid] = b
+		} else if rid == b {
+			X.Distribution[cid] = a
+		}
+	}
+}
+
+// Distance returns the number of character pairs playing in the same raid in one genome but not in the other. It does
+// not depend on the raid numbers: relabeled raids are at distance 0, while moving a character between two raids of 20
+// changes 39 pairs and benching it 19.
+func (X *Genome) Distance(Y *Genome) int {
+	// Characters in each raid of X and raid of Y, the bench being the last raid of both
+	width := Y.RaidCount + 1
+	counts := make([]int, (X.RaidCount+1)*width)
+	for cid, xr := range X.Distribution {
+		yr := Y.Distribution[cid]
+		if xr < 0 {
+			xr = X.RaidCount
+		}
+		if yr < 0 {
+			yr = Y.RaidCount
+		}
+		counts[xr*width+yr] += 1
+	}
+
+	pairs := func(n int) int {
+		return n * (n - 1) / 2
+	}
+	var x, y, both int
+	for a := 0; a < X.RaidCount; a++ {
+		size := 0
+		for b := 0; b <= Y.RaidCount; b++ {
+			size += counts[a*width+b]
+			if b < Y.RaidCount {
+				both += pairs(counts[a*width+b])
+			}
+		}
+		x += pairs(size)
+	}
+	for b := 0; b < Y.RaidCount; b++ {
+		size := 0
+		for a := 0; a <= X.RaidCount; a++ {
+			size += counts[a*width+b]
+		}
+		y += pairs(size)
+	}
+	return x + y - 2*both
+}
@@ -0,0 +1,57 @@
+package raidopt
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name   string
+		x, y   []int
+		xc, yc int // Raid counts
+		want   int
+	}{
+		{"same split", []int{0, 0, 0, 1, 1, 1}, []int{0, 0, 0, 1, 1, 1}, 2, 2, 0},
+		{"swapped raids", []int{0, 0, 0, 1, 1, 1}, []int{1, 1, 1, 0, 0, 0}, 2, 2, 0},
+		{"rotated raids", []int{0, 0, 1, 1, 2, 2, -1}, []int{1, 1, 2, 2, 0, 0, -1}, 3, 3, 0},
+		{"moved character", []int{0, 0, 0, 1, 1, 1}, []int{0, 0, 1, 1, 1, 1}, 2, 2, 5},
+		{"moved character in swapped raids", []int{0, 0, 0, 1, 1, 1}, []int{1, 1, 0, 0, 0, 0}, 2, 2, 5},
+		{"benched character", []int{0, 0, 0, 1, 1, 1}, []int{0, 0, -1, 1, 1, 1}, 2, 2, 2},
+		{"merged raids", []int{0, 0, 1, 1}, []int{0, 0, 0, 0}, 2, 1, 4},
+	}
+
+	for _, tt := range tests {
+		X := &Genome{RaidCount: tt.xc, Distribution: tt.x}
+		Y := &Genome{RaidCount: tt.yc, Distribution: tt.y}
+		if d := X.Distance(Y); d != tt.want {
+			t.Errorf("%s: distance %d, want %d", tt.name, d, tt.want)
+		}
+		if d := Y.Distance(X); d != tt.want {
+			t.Errorf("%s: reverse distance %d, want %d", tt.name, d, tt.want)
+		}
+	}
+}
+
+func TestDistanceRelabeled(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		X := &Genome{RaidCount: 2 + rng.Intn(5), Distribution: make([]int, 60)}
+		for cid := range X.Distribution {
+			X.Distribution[cid] = rng.Intn(X.RaidCount+1) - 1
+		}
+
+		Y := &Genome{RaidCount: X.RaidCount, Distribution: make([]int, len(X.Distribution))}
+		perm := rng.Perm(X.RaidCount)
+		for cid, rid := range X.Distribution {
+			Y.Distribution[cid] = rid
+			if rid >= 0 {
+				Y.Distribution[cid] = perm[rid]
+			}
+		}
+
+		if d := X.Distance(Y); d != 0 {
+			t.Errorf("raids relabeled by %v: distance %d, want 0", perm, d)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package raidopt
+
+import (
+	"math"
+	"math/rand"
+)
+
+const makeRaidAttempts = 1000
+
+func (p *Problem) MakeRaid(rng *rand.Rand) *Genome {
+	if p.seed != nil && rng.Intn(100) < seedRate {
+		return p.seed.Clone().(*Genome)
+	}
+
+	raidCount := p.raidCounts[rng.Intn(len(p.raidCounts))]
+	for attempt := 0; attempt < makeRaidAttempts; attempt++ {
+		if X := p.TryMakeRaid(rng, raidCount); X.Repair(rng) {
+			return X
+		}
+	}
+
+	// Random attempts keep failing for this raid count, start from the distribution found by the feasibility analysis
+	return p.witnesses[raidCount].Clone().(*Genome)
+}
+
+// TryMakeRaid makes a single attempt at building a random distribution with the given number of raids. The
+// returned genome is not guaranteed to be viable.
+func (p *Problem) TryMakeRaid(rng *rand.Rand, raidCount int) *Genome {
+	X := Genome{
+		problem:      p,
+		RaidCount:    raidCount,
+		Distribution: make([]int, len(p.Roster)),
+		Roles:        make([]Role, len(p.Roster)),
+	}
+	for cid, char := range p.Roster {
+		X.Distribution[cid] = -1
+		X.Roles[cid] = char.Role
+	}
+
+	// Keep track of which raids the player is participating in
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+
+	// Prepare tank spots, every raid requires MinTanks and may accept up to MaxTanks
+	tankCount := X.RaidCount * p.Constraints.MinTanks
+	tankSpots := make([]int, tankCount)
+	for i := range tankSpots {
+		tankSpots[i] = i / p.Constraints.MinTanks
+	}
+	bonusTankCount := X.RaidCount * (p.Constraints.MaxTanks - p.Constraints.MinTanks)
+	bonusTankSpots := make([]int, bonusTankCount)
+	for i := range bonusTankSpots {
+		bonusTankSpots[i] = i / (p.Constraints.MaxTanks - p.Constraints.MinTanks)
+	}
+
+	rng.Shuffle(tankCount, func(i, j int) {
+		tankSpots[i], tankSpots[j] = tankSpots[j], tankSpots[i]
+	})
+	rng.Shuffle(bonusTankCount, func(i, j int) {
+		bonusTankSpots[i], bonusTankSpots[j] = bonusTankSpots[j], bonusTankSpots[i]
+	})
+
+	// *** Dispatch tanks ***
+
+	raidTanksCount := make([]int, X.RaidCount)
+	startTankSpot, startBonusTankSpot := 0, 0
+	for _, group := range p.dispatchGroups(&p.roleIndex.Tank) {
+		// Shuffle chars in the group
+		chars := make([]int, len(group))
+		copy(chars, group)
+		rng.Shuffle(len(chars), func(i, j int) {
+			chars[i], chars[j] = chars[j], chars[i]
+		})
+
+	tank:
+		for _, cid := range chars {
+			char := p.Roster[cid]
+			if X.Distribution[cid] >= 0 {
+				continue // Already dispatched in another role
+			}
+			X.Roles[cid] = Tank
+
+			// Attempt to find a tank spot for this char
+			for spot := startTankSpot; spot < tankCount; spot++ {
+				raid := tankSpots[spot]
+
+				if playerRaids.Has(char.Player, raid) {
+					continue // Player already in this raid
+				}
+				if !p.allowed(X.Distribution, cid, raid) {
+					continue // Forbidden by the rules
+				}
+
+				X.Distribution[cid] = raid
+				playerRaids.Add(char.Player, raid)
+				raidTanksCount[raid] += 1
+
+				tankSpots[startTankSpot], tankSpots[spot] = tankSpots[spot], tankSpots[startTankSpot]
+				startTankSpot += 1
+
+				continue tank
+			}
+
+			// Bonus spots are only used to fit extra main tanks, alts are left to mutations
+			if char.Main {
+				for spot := startBonusTankSpot; spot < bonusTankCount; spot++ {
+					raid := bonusTankSpots[spot]
+
+					if playerRaids.Has(char.Player, raid) {
+						continue // Player already in this raid
+					}
+					if !p.allowed(X.Distribution, cid, raid) {
+						continue // Forbidden by the rules
+					}
+
+					X.Distribution[cid] = raid
+					playerRaids.Add(char.Player, raid)
+					raidTanksCount[raid] += 1
+
+					bonusTankSpots[startBonusTankSpot], bonusTankSpots[spot] = bonusTankSpots[spot], bonusTankSpots[startBonusTankSpot]
+					startBonusTankSpot += 1
+
+					continue tank
+				}
+			}
+
+			// No spot left for this char, a benched main will make the distribution unviable
+			X.Roles[cid] = char.Role
+		}
+	}
+
+	// FIXME: startTankSpot != tankCount might happen if playing with more tanks than required per player because we
+	// may assign players with less chars first leaving the player with the most chars to fill every last slots...
+	// The resulting distribution will not be viable and the caller will try again.
+
+	// *** Dispatch healers ***
+
+	// Find out how many healers are actually spottable for this number of raids, once tanks are dispatched
+	spottableHealers := 0
+	for _, chars := range p.playerCharacters {
+		playerHealers := 0
+		for _, cid := range chars {
+			if X.Distribution[cid] < 0 && p.Roster[cid].HasRole(Healer) {
+				playerHealers += 1
+			}
+			if playerHealers == X.RaidCount {
+				break
+			}
+		}
+		spottableHealers += playerHealers
+	}
+
+	// Prepare healers spots
+	healPerRaid := int(math.Ceil(float64(spottableHealers) / float64(X.RaidCount)))
+	healCount := healPerRaid * X.RaidCount
+	healSpots := make([]int, healCount)
+	for i := range healSpots {
+		healSpots[i] = i / healPerRaid
+	}
+
+	rng.Shuffle(healCount, func(i, j int) {
+		healSpots[i], healSpots[j] = healSpots[j], healSpots[i]
+	})
+
+	raidHealsCount := make([]int, X.RaidCount)
+	startHealSpot := 0
+	for _, group := range p.dispatchGroups(&p.roleIndex.Heal) {
+		// Shuffle chars in the group
+		chars := make([]int, len(group))
+		copy(chars, group)
+		rng.Shuffle(len(chars), func(i, j int) {
+			chars[i], chars[j] = chars[j], chars[i]
+		})
+
+	healer:
+		for _, cid := range chars {
+			char := p.Roster[cid]
+			if X.Distribution[cid] >= 0 {
+				continue // Already dispatched in another role
+			}
+			X.Roles[cid] = Healer
+
+			// Attempt to find a healer spot for this char
+			for spot := startHealSpot; spot < healCount; spot++ {
+				raid := healSpots[spot]
+
+				if playerRaids.Has(char.Player, raid) {
+					continue // Player already in this raid
+				}
+				if !p.allowed(X.Distribution, cid, raid) {
+					continue // Forbidden by the rules
+				}
+
+				X.Distribution[cid] = raid
+				playerRaids.Add(char.Player, raid)
+				raidHealsCount[raid] += 1
+
+				healSpots[startHealSpot], healSpots[spot] = healSpots[spot], healSpots[startHealSpot]
+				startHealSpot += 1
+
+				continue healer
+			}
+
+			// No spot left for this char, a benched main will make the distribution unviable
+			X.Roles[cid] = char.Role
+		}
+	}
+
+	// *** Dispatch DPSes ***
+
+	requiredSlotsCount := 0
+	bonusSlotsCount := 0
+	dpsCountPerRaid := make([]int, X.RaidCount*2)
+	for i := 0; i < X.RaidCount; i++ {
+		rh, rt := float64(raidHealsCount[i]), float64(raidTanksCount[i])
+		required := Max(0, int(math.Ceil(Max(float64(p.Constraints.MinRaidSize)-rt-rh, rh/p.Constraints.HealerMaxRatio-rh-rt))))
+		bonus := Max(0, int(math.Floor(rh/p.Constraints.HealerMinRatio-rh-rt))-required)
+
+		dpsCountPerRaid[i*2] = required
+		dpsCountPerRaid[i*2+1] = bonus
+
+		requiredSlotsCount += required
+		bonusSlotsCount += bonus
+	}
+
+	requiredSlots := make([]int, 0, requiredSlotsCount)
+	bonusSlots := make([]int, 0, bonusSlotsCount)
+	for i := 0; i < X.RaidCount; i++ {
+		for j, count := 0, dpsCountPerRaid[i*2]; j < count; j++ {
+			requiredSlots = append(requiredSlots, i)
+		}
+		for j, count := 0, dpsCountPerRaid[i*2+1]; j < count; j++ {
+			bonusSlots = append(bonusSlots, i)
+		}
+	}
+	rng.Shuffle(requiredSlotsCount, func(i, j int) {
+		requiredSlots[i], requiredSlots[j] = requiredSlots[j], requiredSlots[i]
+	})
+	rng.Shuffle(bonusSlotsCount, func(i, j int) {
+		bonusSlots[i], bonusSlots[j] = bonusSlots[j], bonusSlots[i]
+	})
+
+	startRequiredSlot, startBonusSlot := 0, 0
+	for _, group := range p.dispatchGroups(&p.roleIndex.Dps) {
+		// Shuffle chars in the group
+		chars := make([]int, len(group))
+		copy(chars, group)
+		rng.Shuffle(len(chars), func(i, j int) {
+			chars[i], chars[j] = chars[j], chars[i]
+		})
+
+	dps:
+		for _, cid := range chars {
+			char := p.Roster[cid]
+			if X.Distribution[cid] >= 0 {
+				continue // Already dispatched in another role
+			}
+			X.Roles[cid] = char.FirstRole(Melee | Ranged)
+
+			// Attempt to find a dps spot for this char
+			for spot := startRequiredSlot; spot < requiredSlotsCount; spot++ {
+				raid := requiredSlots[spot]
+				if playerRaids.Has(char.Player, raid) {
+					continue // Player already in this raid
+				}
+				if !p.allowed(X.Distribution, cid, raid) {
+					continue // Forbidden by the rules
+				}
+				X.Distribution[cid] = raid
+				playerRaids.Add(char.Player, raid)
+				requiredSlots[startRequiredSlot], requiredSlots[spot] = requiredSlots[spot], requiredSlots[startRequiredSlot]
+				startRequiredSlot += 1
+				continue dps
+			}
+			for spot := startBonusSlot; spot < bonusSlotsCount; spot++ {
+				raid := bonusSlots[spot]
+				if playerRaids.Has(char.Player, raid) {
+					continue // Player already in this raid
+				}
+				if !p.allowed(X.Distribution, cid, raid) {
+					continue // Forbidden by the rules
+				}
+				X.Distribution[cid] = raid
+				playerRaids.Add(char.Player, raid)
+				bonusSlots[startBonusSlot], bonusSlots[spot] = bonusSlots[spot], bonusSlots[startBonusSlot]
+				startBonusSlot += 1
+				continue dps
+			}
+
+			// No spot left for this char, a benched main will make the distribution unviable
+			X.Roles[cid] = char.Role
+		}
+	}
+
+	return &X
+}
+
+// Returns the characters of the role index in dispatch order: pinned mains and alts first, so that they get a spot of
+// their raid before other characters take them, then mains, alts and characters for which the role is not the
+// preferred one. Pinned characters are listed twice, and skipped once dispatched. Pinned characters of the latter
+// group keep their turn, so that they are dispatched in their preferred role first.
+func (p *Problem) dispatchGroups(ridx *RoleIndex) [][]int {
+	var pinned []int
+	for _, group := range [][]int{ridx.Mains, ridx.Alts} {
+		for _, cid := range group {
+			if p.pinned(cid) {
+				pinned = append(pinned, cid)
+			}
+		}
+	}
+	return [][]int{pinned, ridx.Mains, ridx.Alts, ridx.Flex}
+}
@@ -0,0 +1,26 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// Mutate applies a mutation operator picked according to how the operators performed in the population
+func (X *Genome) Mutate(rng *rand.Rand) {
+	X.problem.operatorSelector(rng).mutate(X, rng)
+}
+
+func (X *Genome) MutResize(rng *rand.Rand) {
+	// Shrinking always drops the last raid, start by swapping a random raid with the last one without touching raid
+	// comp. Pins and availability are tied to raid numbers, so raids are only swapped if the rules allow it.
+	if rid, last := rng.Intn(X.RaidCount), X.RaidCount-1; rid != last && X.canSwapRaids(rid, last) {
+		X.swapRaids(rid, last)
+	}
+
+	// Either Shrink or Expand the roster
+	switch rng.Intn(2) {
+	case 0:
+		X.MutResizeShrink(rng)
+	case 1:
+		X.MutResizeExpand(rng)
+	}
+}
@@ -0,0 +1,240 @@
+package raidopt
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// A mutation operator picked by Genome.Mutate
+type mutationOperator struct {
+	name   string
+	weight float64 // Initial selection weight
+	apply  func(X *Genome, rng *rand.Rand)
+}
+
+// Mutation operators, in the order of their statistics. Initialized by init as operators refer back to it.
+var mutationOperators []mutationOperator
+
+func init() {
+	mutationOperators = []mutationOperator{
+		{"resize", 5, (*Genome).MutResize},        // Resize the raid comp
+		{"role", 5, (*Genome).MutRole},            // Switch the role of one character
+		{"swap", 20, (*Genome).MutSwap},           // Swap two characters of the same player
+		{"trade", 15, (*Genome).MutTrade},         // Trade two characters of different players
+		{"chain", 10, (*Genome).MutEjectionChain}, // Introduce one character, moving others along
+		{"rotate", 5, (*Genome).MutRotate},        // Rotate the characters of one player
+		{"introduce", 20, (*Genome).MutIntroduce}, // Introduce one character
+		{"bench", 20, (*Genome).MutBench},         // Bench one character
+	}
+}
+
+const (
+	adaptationRate  = 0.05 // Weight of the last outcome in the estimated quality of an operator
+	minOperatorRate = 0.02 // Selection probability every operator keeps, so that it can recover
+	maxRetries      = 3    // Candidates an operator tries after the first one was rejected
+)
+
+// OperatorStats describes how a mutation operator performed in a population
+type OperatorStats struct {
+	Name         string
+	Attempts     int     // Number of times the operator was picked
+	Retries      int     // Candidates tried after a previous one was rejected
+	Fallbacks    int     // Times the operator had nothing to apply and used another one
+	Rejections   int     // Mutations that were not viable and could not be repaired
+	Successes    int     // Mutations that changed the genome
+	Improvements int     // Mutations that improved the fitness
+	Gain         float64 // Total fitness improvement
+	Rate         float64 // Current selection probability
+}
+
+// Events recorded by mutation operators
+type operatorEvent int
+
+const (
+	eventRetry operatorEvent = iota
+	eventFallback
+	eventRejection
+)
+
+// Picks mutation operators by probability matching: each operator is selected in proportion to the fitness
+// improvement it recently brought, relative to the fitness of the mutated genome
+type operatorSelector struct {
+	stats   []OperatorStats
+	quality []float64
+	enabled []bool
+	current int // Operator being applied, -1 if none
+}
+
+func (p *Problem) newOperatorSelector() *operatorSelector {
+	sel := &operatorSelector{
+		stats:   make([]OperatorStats, len(mutationOperators)),
+		quality: make([]float64, len(mutationOperators)),
+		enabled: make([]bool, len(mutationOperators)),
+		current: -1,
+	}
+	var total float64
+	for i, op := range mutationOperators {
+		sel.stats[i].Name = op.name
+		if op.name == "resize" && p.minRaids == p.maxRaids {
+			continue // There is nothing to resize
+		}
+		sel.enabled[i] = true
+		sel.quality[i] = op.weight
+		total += op.weight
+	}
+	for i := range sel.quality {
+		sel.quality[i] /= total
+	}
+	sel.updateRates()
+	return sel
+}
+
+func (sel *operatorSelector) updateRates() {
+	var total float64
+	var enabled int
+	for i, q := range sel.quality {
+		if sel.enabled[i] {
+			total += q
+			enabled += 1
+		}
+	}
+	for i, q := range sel.quality {
+		if !sel.enabled[i] {
+			continue
+		}
+		if total > 0 {
+			sel.stats[i].Rate = minOperatorRate + (1-float64(enabled)*minOperatorRate)*q/total
+		} else {
+			sel.stats[i].Rate = 1 / float64(enabled)
+		}
+	}
+}
+
+func (sel *operatorSelector) pick(rng *rand.Rand) int {
+	r := rng.Float64()
+	last := 0
+	for i, s := range sel.stats {
+		if s.Rate == 0 {
+			continue
+		}
+		if r < s.Rate {
+			return i
+		}
+		r -= s.Rate
+		last = i
+	}
+	return last // Rounding errors
+}
+
+// Applies a mutation operator. The operator is rewarded once the genome is evaluated by the model, the genome is only
+// evaluated beforehand if it changed since its last evaluation, after a crossover.
+func (sel *operatorSelector) mutate(X *Genome, rng *rand.Rand) {
+	if !X.evaluated {
+		X.Evaluate()
+	}
+	i := sel.pick(rng)
+	dist := make([]int, len(X.Distribution))
+	roles := make([]Role, len(X.Roles))
+	copy(dist, X.Distribution)
+	copy(roles, X.Roles)
+
+	sel.current = i
+	mutationOperators[i].apply(X, rng)
+	sel.current = -1
+
+	s := &sel.stats[i]
+	s.Attempts += 1
+	if X.changed(dist, roles) {
+		s.Successes += 1
+	}
+	X.pending = &pendingMutation{selector: sel, operator: i, before: X.fitness}
+	X.evaluated = false
+}
+
+// A mutation waiting for the evaluation of the mutated genome. Genomes are evaluated by their population right after
+// being bred, so the selector is only updated by its population.
+type pendingMutation struct {
+	selector *operatorSelector
+	operator int
+	before   float64 // Fitness before the mutation
+}
+
+// Rewards the operator with the relative fitness improvement it brought
+func (m *pendingMutation) reward(after float64) {
+	sel, s := m.selector, &m.selector.stats[m.operator]
+	var reward float64
+	if after < m.before {
+		s.Improvements += 1
+		s.Gain += m.before - after
+		reward = (m.before - after) / m.before
+	}
+	sel.quality[m.operator] += adaptationRate * (reward - sel.quality[m.operator])
+	sel.updateRates()
+}
+
+// Records an event of the operator the population using the random generator is applying, if any. Operators applied
+// as a fallback report their events to the operator that was picked.
+func (X *Genome) record(rng *rand.Rand, event operatorEvent) {
+	sel := X.problem.operatorSelector(rng)
+	if sel.current < 0 {
+		return
+	}
+	s := &sel.stats[sel.current]
+	switch event {
+	case eventRetry:
+		s.Retries += 1
+	case eventFallback:
+		s.Fallbacks += 1
+	case eventRejection:
+		s.Rejections += 1
+	}
+}
+
+// Returns whether the genome differs from the given distribution
+func (X *Genome) changed(dist []int, roles []Role) bool {
+	for cid, rid := range X.Distribution {
+		if rid != dist[cid] || (rid >= 0 && X.Roles[cid] != roles[cid]) {
+			return true
+		}
+	}
+	return false
+}
+
+// The operator selector of each population. Populations are identified by their random generator, the only
+// population state passed to the genomes.
+type operatorSelectors struct {
+	selectors sync.Map // Random generator to selector
+
+	mu    sync.Mutex
+	order []*operatorSelector // Selectors in population order
+}
+
+// Returns the operator selector of the population using the random generator, creating it if needed. The optimizer
+// gets the selector of each population when creating it, so that they follow the population order.
+func (p *Problem) operatorSelector(rng *rand.Rand) *operatorSelector {
+	ops := &p.operators
+	if sel, found := ops.selectors.Load(rng); found {
+		return sel.(*operatorSelector)
+	}
+
+	sel, loaded := ops.selectors.LoadOrStore(rng, p.newOperatorSelector())
+	if !loaded {
+		ops.mu.Lock()
+		ops.order = append(ops.order, sel.(*operatorSelector))
+		ops.mu.Unlock()
+	}
+	return sel.(*operatorSelector)
+}
+
+// OperatorStats returns the statistics of the mutation operators of every population, in population order
+func (p *Problem) OperatorStats() [][]OperatorStats {
+	ops := &p.operators
+	ops.mu.Lock()
+	defer ops.mu.Unlock()
+	stats := make([][]OperatorStats, len(ops.order))
+	for i, sel := range ops.order {
+		stats[i] = make([]OperatorStats, len(sel.stats))
+		copy(stats[i], sel.stats)
+	}
+	return stats
+}
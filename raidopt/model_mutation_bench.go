@@ -0,0 +1,48 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+func (X *Genome) MutBench(rng *rand.Rand) {
+	p := X.problem
+	stats := make([]RaidComp, X.RaidCount)
+	benchable := make([]int, len(X.Distribution))
+	j := 0
+
+	for cid, rid := range X.Distribution {
+		char := &p.Roster[cid]
+		if rid >= 0 {
+			stats[rid].Add(X.Roles[cid])
+			if char.Main || p.pinned(cid) {
+				continue // Mains and pinned characters are immune to benching
+			}
+			benchable[j] = cid
+			j++
+		}
+	}
+
+	// Remove impossible benches
+	for i := 0; i < j; {
+		rid := X.Distribution[benchable[i]]
+		if p.Constraints.Check(stats[rid].Without(X.Roles[benchable[i]])) != ConstraintNone {
+			// Benching this character would break the raid size, tank count or healer ratio
+			benchable[i] = benchable[j-1]
+			j--
+			continue
+		}
+		i++
+	}
+
+	if j < 1 {
+		// If we cannot bench anything, let's introduce someone instead
+		X.record(rng, eventFallback)
+		X.MutIntroduce(rng)
+		return
+	}
+
+	// Benching a random char
+	Y := X.Clone().(*Genome)
+	Y.Distribution[benchable[rng.Intn(j)]] = -1
+	X.commit(Y.Distribution, Y.Roles, Y.RaidCount, rng)
+}
@@ -0,0 +1,108 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// Maximum number of characters ejected by a single ejection chain
+const maxChainLength = 4
+
+// MutEjectionChain introduces a benched character into a raid, ejecting a character playing the same role that joins
+// another raid in turn, and so on until a character finds a free spot or the chain is too long. The last ejected
+// character is then benched, unless it is a main.
+func (X *Genome) MutEjectionChain(rng *rand.Rand) {
+	if !X.ejectionChain(rng) {
+		// If the chain is stuck, let's introduce someone instead
+		X.record(rng, eventFallback)
+		X.MutIntroduce(rng)
+	}
+}
+
+// Applies an ejection chain starting from a random benched character, returns false if none could be applied
+func (X *Genome) ejectionChain(rng *rand.Rand) bool {
+	p := X.problem
+	stats := make([]RaidComp, X.RaidCount)
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+	benched := make([]int, 0, len(p.Roster))
+
+	for cid, rid := range X.Distribution {
+		if rid >= 0 {
+			stats[rid].Add(X.Roles[cid])
+			playerRaids.Add(p.Roster[cid].Player, rid)
+		} else if !p.rules.bench[cid] {
+			benched = append(benched, cid)
+		}
+	}
+
+	if len(benched) == 0 {
+		return false
+	}
+
+	dist := make([]int, len(X.Distribution))
+	roles := make([]Role, len(X.Roles))
+	copy(dist, X.Distribution)
+	copy(roles, X.Roles)
+	moved := make(map[int]bool)
+
+	cid, from := benched[rng.Intn(len(benched))], -1
+	for step := 0; step <= maxChainLength; step++ {
+		char := p.Roster[cid]
+		moved[cid] = true
+
+		// Ends the chain if the character fits in a raid without ejecting anyone
+		raids := rng.Perm(X.RaidCount)
+		for _, rid := range raids {
+			if rid == from || playerRaids.Has(char.Player, rid) || !p.allowed(dist, cid, rid) {
+				continue
+			}
+			for _, role := range char.Roles {
+				if p.Constraints.Check(stats[rid].With(role)) == ConstraintNone {
+					dist[cid], roles[cid] = rid, role
+					return X.commit(dist, roles, X.RaidCount, rng)
+				}
+			}
+		}
+
+		if step == maxChainLength {
+			break
+		}
+
+		// Otherwise eject a character of the same role, which continues the chain. The last ejected character must be
+		// an alt as it ends up on the bench.
+		last := step == maxChainLength-1
+		ejected := -1
+		for _, rid := range raids {
+			if rid == from || !p.allowed(dist, cid, rid) {
+				continue
+			}
+			for oid, orid := range dist {
+				if orid != rid || moved[oid] || p.pinned(oid) || (last && p.Roster[oid].Main) || !char.HasRole(roles[oid]) {
+					continue
+				}
+				if playerRaids.Has(char.Player, rid) && p.Roster[oid].Player != char.Player {
+					continue // Another character of the player is already playing here
+				}
+				ejected = oid
+				break
+			}
+			if ejected >= 0 {
+				break
+			}
+		}
+		if ejected < 0 {
+			break
+		}
+
+		rid := dist[ejected]
+		playerRaids.Remove(p.Roster[ejected].Player, rid)
+		playerRaids.Add(char.Player, rid)
+		dist[cid], roles[cid] = rid, roles[ejected]
+		dist[ejected] = -1
+		cid, from = ejected, rid
+	}
+
+	if from < 0 || p.Roster[cid].Main {
+		return false
+	}
+	return X.commit(dist, roles, X.RaidCount, rng) // The last ejected character stays on the bench
+}
@@ -0,0 +1,65 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+func (X *Genome) MutIntroduce(rng *rand.Rand) {
+	p := X.problem
+	stats := make([]RaidComp, X.RaidCount)
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+	benched := make([]int, 0, len(p.Roster))
+
+	for cid, rid := range X.Distribution {
+		char := &p.Roster[cid]
+		if rid >= 0 {
+			playerRaids.Add(char.Player, rid)
+			stats[rid].Add(X.Roles[cid])
+		} else {
+			benched = append(benched, cid)
+		}
+	}
+
+	dist := make([]int, len(X.Distribution))
+	roles := make([]Role, len(X.Roles))
+	copy(dist, X.Distribution)
+	copy(roles, X.Roles)
+
+	retries := 0
+	if len(benched) > 0 {
+		for _, bid := range rng.Perm(len(benched)) {
+			cid := benched[bid]
+			char := &p.Roster[cid]
+
+			for _, rid := range rng.Perm(X.RaidCount) {
+				if playerRaids.Has(char.Player, rid) {
+					continue // This player is already playing here
+				}
+				if !p.allowed(dist, cid, rid) {
+					continue // Forbidden by the rules
+				}
+
+				// Attempt every role of the character in random order
+				for _, ri := range rng.Perm(len(char.Roles)) {
+					role := char.Roles[ri]
+					if p.Constraints.Check(stats[rid].With(role)) != ConstraintNone {
+						continue // Introducing this character would break the raid size, tank count or healer ratio
+					}
+
+					dist[cid] = rid
+					roles[cid] = role
+					if X.commit(dist, roles, X.RaidCount, rng) || retries == maxRetries {
+						return
+					}
+					dist[cid], roles[cid] = -1, X.Roles[cid]
+					retries += 1
+					X.record(rng, eventRetry)
+				}
+			}
+		}
+	}
+
+	// If we cannot introduce anything, let's bench someone instead
+	X.record(rng, eventFallback)
+	X.MutBench(rng)
+}
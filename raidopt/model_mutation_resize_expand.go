@@ -0,0 +1,26 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// MutResizeExpand forms a new raid from the bench and from the characters other raids can spare without breaking
+// their composition
+func (X *Genome) MutResizeExpand(rng *rand.Rand) {
+	p := X.problem
+	if !p.feasible(X.RaidCount + 1) {
+		return
+	}
+
+	Y := X.Clone().(*Genome)
+	Y.RaidCount += 1
+	p.fillRaid(Y, X.RaidCount, true, rng)
+
+	if !Y.Repair(rng) {
+		X.record(rng, eventRejection)
+		return
+	}
+	X.RaidCount = Y.RaidCount
+	copy(X.Distribution, Y.Distribution)
+	copy(X.Roles, Y.Roles)
+}
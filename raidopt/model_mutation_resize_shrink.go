@@ -1,60 +1,50 @@
-package main
+package raidopt
 
 import (
-	"log"
-	"math"
 	"math/rand"
 )
 
 func (X *Genome) MutResizeShrink(rng *rand.Rand) {
-	if X.RaidCount == minRaids {
+	p := X.problem
+	if !p.feasible(X.RaidCount - 1) {
 		return
 	}
 
 	droppedRaid := X.RaidCount - 1
-	basePlayerRaids := make([]int, len(players))
+	basePlayerRaids := p.MakePlayerRaids(X.RaidCount)
 
-	type RaidStats struct {
-		Count         float64
-		Healers       float64
-		ExtraCapacity float64
-	}
-
-	baseStats := make([]RaidStats, droppedRaid)
+	baseStats := make([]RaidComp, droppedRaid)
 
 	droppedMains := make([]int, 0)
 	droppedAlts := make([]int, 0)
 
 	for cid, rid := range X.Distribution {
 		if rid >= 0 {
-			char := roster[cid]
-			basePlayerRaids[char.Player] |= 1 << rid
+			char := p.Roster[cid]
+			basePlayerRaids.Add(char.Player, rid)
 
 			if rid == droppedRaid {
+				if p.pinned(cid) {
+					return // Pinned characters cannot leave their raid
+				}
 				if char.Main {
 					droppedMains = append(droppedMains, cid)
 				} else {
 					droppedAlts = append(droppedAlts, cid)
 				}
 			} else {
-				baseStats[rid].Count += 1
-				if char.Role == Healer {
-					baseStats[rid].Healers += 1
-				}
+				baseStats[rid].Add(X.Roles[cid])
 			}
 		}
 	}
 
-	for rid := range baseStats {
-		baseStats[rid].ExtraCapacity = math.Floor(baseStats[rid].Healers/healerMinRatio - baseStats[rid].Count)
-	}
-
-	dist := make([]int, len(roster))
-	playerRaids := make([]int, len(basePlayerRaids))
-	stats := make([]RaidStats, droppedRaid)
-again:
+	dist := make([]int, len(p.Roster))
+	roles := make([]Role, len(p.Roster))
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+	stats := make([]RaidComp, droppedRaid)
 	copy(dist, X.Distribution)
-	copy(playerRaids, basePlayerRaids)
+	copy(roles, X.Roles)
+	playerRaids.CopyFrom(basePlayerRaids)
 	copy(stats, baseStats)
 
 	rng.Shuffle(len(droppedMains), func(i, j int) {
@@ -67,26 +57,37 @@ again:
 	// Inject mains back into remaining groups
 nextMain:
 	for _, cid := range droppedMains {
-		char := roster[cid]
+		char := p.Roster[cid]
 	nextRaid:
 		for _, rid := range rng.Perm(droppedRaid) {
 		retry:
-			if playerRaids[char.Player]&(1<<rid) == 0 {
+			if !p.allowed(dist, cid, rid) {
+				continue nextRaid // Forbidden by the rules
+			}
+			if !playerRaids.Has(char.Player, rid) {
 				// Player is not in that raid, the actual method to inject the char depends on the role
-				switch char.Role {
+				switch roles[cid] {
 				case Tank:
-					// For a tank, we need to boot one of them onto the bench
+					// For a tank, we attempt to add it to the comp if there is room and it does not break ratio
+					if p.Constraints.TanksOk(stats[rid].With(Tank)) && p.Constraints.ExtraCapacity(stats[rid]) > 0 {
+						dist[cid] = rid
+						playerRaids.Add(char.Player, rid)
+						stats[rid].Add(Tank)
+						continue nextMain
+					}
+
+					// Otherwise, we need to boot one of them onto the bench
 					for _, oid := range rng.Perm(len(dist)) {
 						if dist[oid] != rid {
 							continue // This char is not in the target raid
 						}
-						other := roster[oid]
-						if other.Role == Tank && !other.Main {
+						other := p.Roster[oid]
+						if roles[oid] == Tank && !other.Main && !p.pinned(oid) {
 							// We found a non-main tank in the target raid. Boot it to the bench.
 							dist[oid] = -1
-							playerRaids[other.Player] &= ^(1 << rid)
+							playerRaids.Remove(other.Player, rid)
 							dist[cid] = rid
-							playerRaids[char.Player] |= (1 << rid)
+							playerRaids.Add(char.Player, rid)
 
 							continue nextMain
 						}
@@ -97,35 +98,30 @@ nextMain:
 
 				case Healer:
 					// For a healer, we attempt to add it to the comp if it does not break ratio
-					newRatio := (stats[rid].Healers + 1) / (stats[rid].Count + 1)
-					if newRatio > healerMaxRatio {
+					next := stats[rid].With(Healer)
+					if next.HealerRatio() > p.Constraints.HealerMaxRatio || next.Count > p.Constraints.MaxRaidSize {
 						continue nextRaid
 					}
 
 					dist[cid] = rid
-					playerRaids[char.Player] |= (1 << rid)
-
-					// Update stats
-					stats[rid].Healers += 1
-					stats[rid].Count += 1
-					stats[rid].ExtraCapacity = math.Floor(stats[rid].Healers/healerMinRatio - stats[rid].Count)
+					playerRaids.Add(char.Player, rid)
+					stats[rid] = next
 					continue nextMain
 
 				case Melee, Ranged:
 					// For a DPS, we attempt to use one of the ExtraCapacity if possible, otherwise we boot an alt from the raid
-					if stats[rid].ExtraCapacity > 0 {
-						stats[rid].Count += 1
-						stats[rid].ExtraCapacity -= 1
+					if p.Constraints.ExtraCapacity(stats[rid]) > 0 {
+						stats[rid].Add(roles[cid])
 					} else {
 						for _, oid := range rng.Perm(len(dist)) {
 							if dist[oid] != rid {
 								continue // This char is not in the target raid
 							}
-							other := roster[oid]
-							if other.Role == char.Role && !other.Main {
+							other := p.Roster[oid]
+							if roles[oid] == roles[cid] && !other.Main && !p.pinned(oid) {
 								// We found a non-main dps in the target raid. Boot it to the bench.
 								dist[oid] = -1
-								playerRaids[other.Player] &= ^(1 << rid)
+								playerRaids.Remove(other.Player, rid)
 								goto altDpsReplaced
 							}
 						}
@@ -136,7 +132,7 @@ nextMain:
 
 				altDpsReplaced:
 					dist[cid] = rid
-					playerRaids[char.Player] |= (1 << rid)
+					playerRaids.Add(char.Player, rid)
 					continue nextMain
 				}
 			} else {
@@ -145,9 +141,9 @@ nextMain:
 					if dist[oid] != rid {
 						continue // This char is not in the target raid
 					}
-					other := roster[oid]
-					if other.Player == char.Player {
-						if other.Role == char.Role || other.Role == Melee || other.Role == Ranged {
+					other := p.Roster[oid]
+					if other.Player == char.Player && !p.pinned(oid) {
+						if roles[oid] == roles[cid] || roles[oid] == Melee || roles[oid] == Ranged {
 							// Same role or replacing DPS, just replace alt
 							dist[oid] = -1
 							dist[cid] = rid
@@ -156,16 +152,17 @@ nextMain:
 							// Switching from tank/healer to non-tank/healer, we need to bring another tank/healer from the bench
 							for jid := range dist {
 								if dist[jid] < 0 {
-									joker := roster[jid]
-									if joker.Role != other.Role || joker.Player == char.Player || playerRaids[joker.Player]&(1<<rid) != 0 {
+									joker := p.Roster[jid]
+									if !joker.HasRole(roles[oid]) || joker.Player == char.Player || playerRaids.Has(joker.Player, rid) || !p.allowed(dist, jid, rid) {
 										continue
 									}
 
 									dist[oid] = -1
 									dist[jid] = rid
+									roles[jid] = roles[oid]
 
-									playerRaids[other.Player] &= ^(1 << rid)
-									playerRaids[joker.Player] |= (1 << rid)
+									playerRaids.Remove(other.Player, rid)
+									playerRaids.Add(joker.Player, rid)
 
 									goto retry
 								}
@@ -176,8 +173,8 @@ nextMain:
 			}
 		}
 
-		log.Printf("Failed to insert %s into remaining raids, aborting", char)
-		return
+		// Unable to insert the main into the remaining raids, leave it to the repair
+		dist[cid] = -1
 	}
 
 	// Handle dropped alts
@@ -185,10 +182,5 @@ nextMain:
 		dist[cid] = -1 // Put them on the bench
 	}
 
-	if checkViability && !Viable(dist, X.RaidCount-1) {
-		log.Printf("Shrink goto again")
-		goto again
-	}
-	X.RaidCount -= 1
-	copy(X.Distribution, dist)
+	X.commit(dist, roles, X.RaidCount-1, rng)
 }
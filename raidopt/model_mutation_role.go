@@ -0,0 +1,50 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// MutRole switches a placed character to another of its roles
+func (X *Genome) MutRole(rng *rand.Rand) {
+	p := X.problem
+	stats := make([]RaidComp, X.RaidCount)
+	flexible := make([]int, 0, len(p.Roster))
+
+	for cid, rid := range X.Distribution {
+		if rid >= 0 {
+			stats[rid].Add(X.Roles[cid])
+			if p.Roster[cid].Flexible() {
+				flexible = append(flexible, cid)
+			}
+		}
+	}
+
+	retries := 0
+	for _, fid := range rng.Perm(len(flexible)) {
+		cid := flexible[fid]
+		char := &p.Roster[cid]
+		rid, current := X.Distribution[cid], X.Roles[cid]
+
+		for _, ri := range rng.Perm(len(char.Roles)) {
+			role := char.Roles[ri]
+			if role == current {
+				continue
+			}
+			if p.Constraints.Check(stats[rid].Replace(current, role)) != ConstraintNone {
+				continue // Switching role would break the tank count or healer ratio
+			}
+
+			Y := X.Clone().(*Genome)
+			Y.Roles[cid] = role
+			if X.commit(Y.Distribution, Y.Roles, Y.RaidCount, rng) || retries == maxRetries {
+				return
+			}
+			retries += 1
+			X.record(rng, eventRetry)
+		}
+	}
+
+	// If no character can switch role, let's swap characters instead
+	X.record(rng, eventFallback)
+	X.MutSwap(rng)
+}
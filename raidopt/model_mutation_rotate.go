@@ -0,0 +1,61 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// MutRotate rotates the places of every character of a player: each character takes the raid and the role of the
+// next one, the raid comps are therefore unchanged. Players having fewer than three characters are left to MutSwap.
+func (X *Genome) MutRotate(rng *rand.Rand) {
+	p := X.problem
+
+	for _, pid := range rng.Perm(len(p.Players)) {
+		chars := p.playerCharacters[pid]
+		if len(chars) < 3 {
+			continue
+		}
+
+		if dist, roles, ok := X.rotate(chars, 1+rng.Intn(len(chars)-1)); ok {
+			X.commit(dist, roles, X.RaidCount, rng)
+			return
+		}
+	}
+
+	// If no player can be rotated, let's swap two characters instead
+	X.record(rng, eventFallback)
+	X.MutSwap(rng)
+}
+
+// Returns the distribution where each character of the list takes the place of the one shift positions after it, or
+// false if the rotation does not keep the roles or is forbidden by the rules
+func (X *Genome) rotate(chars []int, shift int) ([]int, []Role, bool) {
+	p := X.problem
+	dist := make([]int, len(X.Distribution))
+	roles := make([]Role, len(X.Roles))
+	copy(dist, X.Distribution)
+	copy(roles, X.Roles)
+
+	changed := false
+	for i, cid := range chars {
+		oid := chars[(i+shift)%len(chars)]
+		rid := X.Distribution[oid]
+		if rid == X.Distribution[cid] {
+			continue
+		}
+		char := p.Roster[cid]
+		if (rid < 0 && char.Main) || (rid >= 0 && !char.HasRole(X.Roles[oid])) {
+			return nil, nil, false
+		}
+		dist[cid], changed = rid, true
+		if rid >= 0 {
+			roles[cid] = X.Roles[oid]
+		}
+	}
+
+	for _, cid := range chars {
+		if !p.allowed(dist, cid, dist[cid]) {
+			return nil, nil, false
+		}
+	}
+	return dist, roles, changed
+}
@@ -0,0 +1,64 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+func (X *Genome) MutSwap(rng *rand.Rand) {
+	p := X.problem
+	stats := make([]RaidComp, X.RaidCount)
+
+	for cid, rid := range X.Distribution {
+		if rid >= 0 {
+			stats[rid].Add(X.Roles[cid])
+		}
+	}
+
+	dist := make([]int, len(X.Distribution))
+	copy(dist, X.Distribution)
+
+	roles := make([]Role, len(X.Roles))
+	copy(roles, X.Roles)
+
+	retries := 0
+	for _, aid := range rng.Perm(len(dist)) {
+		a, ar := p.Roster[aid], dist[aid]
+		for _, charIndex := range rng.Perm(len(p.playerCharacters[a.Player])) {
+			bid := p.playerCharacters[a.Player][charIndex]
+			if aid == bid {
+				continue // We got the exact same char
+			}
+
+			b, br := p.Roster[bid], dist[bid]
+
+			if (a.Main && br == -1) || (b.Main && ar == -1) {
+				continue // Cannot swap a main with a char on the bench
+			}
+
+			if !p.allowed(dist, aid, br) || !p.allowed(dist, bid, ar) {
+				continue // Forbidden by the rules
+			}
+
+			if aRole, bRole := X.Roles[aid], X.Roles[bid]; aRole != bRole {
+				// If the chars have different roles, we need to be careful not to break anything
+				if ar >= 0 && p.Constraints.Check(stats[ar].Replace(aRole, bRole)) != ConstraintNone {
+					continue // Swapping these characters would break the tank count or healer ratio
+				}
+				if br >= 0 && p.Constraints.Check(stats[br].Replace(bRole, aRole)) != ConstraintNone {
+					continue // Swapping these characters would break the tank count or healer ratio
+				}
+			}
+
+			dist[aid] = br
+			dist[bid] = ar
+			if X.commit(dist, roles, X.RaidCount, rng) || retries == maxRetries {
+				return
+			}
+			dist[aid], dist[bid] = ar, br
+			retries += 1
+			X.record(rng, eventRetry)
+		}
+	}
+
+	// Nothing can be swapped
+}
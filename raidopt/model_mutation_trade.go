@@ -0,0 +1,73 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// MutTrade exchanges two characters of different players playing the same role, either in two raids or in a raid and
+// on the bench. Each character takes the role of the other, so the raid comps are unchanged.
+func (X *Genome) MutTrade(rng *rand.Rand) {
+	p := X.problem
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+	for cid, rid := range X.Distribution {
+		if rid >= 0 {
+			playerRaids.Add(p.Roster[cid].Player, rid)
+		}
+	}
+
+	dist := make([]int, len(X.Distribution))
+	roles := make([]Role, len(X.Roles))
+	copy(dist, X.Distribution)
+	copy(roles, X.Roles)
+
+	// Returns whether the character can take the place and role of the other one
+	fits := func(cid, oid int) bool {
+		char, rid := p.Roster[cid], dist[oid]
+		if rid < 0 {
+			return !char.Main // Mains cannot be benched
+		}
+		return char.HasRole(roles[oid]) && !playerRaids.Has(char.Player, rid)
+	}
+
+	n, retries := len(dist), 0
+	for _, aid := range rng.Perm(n) {
+		if dist[aid] < 0 || p.pinned(aid) {
+			continue // Trades start from a character in a raid
+		}
+
+		start := rng.Intn(n)
+		for i := 0; i < n; i++ {
+			bid := (start + i) % n
+			ar, br := dist[aid], dist[bid]
+			if ar == br || p.Roster[aid].Player == p.Roster[bid].Player || p.pinned(bid) {
+				continue
+			}
+			if !fits(aid, bid) || !fits(bid, aid) {
+				continue
+			}
+
+			dist[aid], dist[bid] = br, ar
+			if !p.allowed(dist, aid, br) || !p.allowed(dist, bid, ar) {
+				dist[aid], dist[bid] = ar, br
+				continue // Forbidden by the rules
+			}
+
+			if br >= 0 {
+				roles[aid], roles[bid] = roles[bid], roles[aid]
+			} else {
+				roles[bid] = roles[aid]
+			}
+			if X.commit(dist, roles, X.RaidCount, rng) || retries == maxRetries {
+				return
+			}
+			dist[aid], dist[bid] = ar, br
+			roles[aid], roles[bid] = X.Roles[aid], X.Roles[bid]
+			retries += 1
+			X.record(rng, eventRetry)
+		}
+	}
+
+	// If nothing can be traded, let's swap characters of the same player instead
+	X.record(rng, eventFallback)
+	X.MutSwap(rng)
+}
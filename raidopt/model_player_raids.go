@@ -0,0 +1,31 @@
+package raidopt
+
+// PlayerRaids keeps track of which raids each player is participating in
+type PlayerRaids struct {
+	raidCount int
+	bits      BitSet
+}
+
+func (p *Problem) MakePlayerRaids(raidCount int) PlayerRaids {
+	return PlayerRaids{
+		raidCount: raidCount,
+		bits:      MakeBitSet(len(p.Players) * raidCount),
+	}
+}
+
+func (pr PlayerRaids) Has(player, raid int) bool {
+	return pr.bits.Get(player*pr.raidCount + raid)
+}
+
+func (pr PlayerRaids) Add(player, raid int) {
+	pr.bits.Set(player*pr.raidCount+raid, true)
+}
+
+func (pr PlayerRaids) Remove(player, raid int) {
+	pr.bits.Set(player*pr.raidCount+raid, false)
+}
+
+// CopyFrom overwrites the membership with the one of another PlayerRaids having the same raid count
+func (pr PlayerRaids) CopyFrom(other PlayerRaids) {
+	copy(pr.bits, other.bits)
+}
@@ -0,0 +1,255 @@
+package raidopt
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules are assignment constraints on specific characters, on top of the raid composition constraints:
+//
+//	pins: {Alicia: 1}              # Characters that must play in the given raid, starting at 1
+//	together: [[Alicia, Bobby]]    # Characters that must play in the same raid when they are not benched
+//	apart: [[Alice, Bob]]          # Players or characters that must never play in the same raid
+//	bench: [Charlie]               # Characters that must stay on the bench
+//	raids: [Wed 20:00, Sun 15:00]  # Names of the raids, in order, also limiting the number of raids
+//	availability:                  # Raids each player can attend, players not listed can attend every raid
+//	  Alice: [Wed 20:00]
+//
+// When fewer raids than named ones are formed, the first ones are used.
+type Rules struct {
+	Pins         map[string]int      `json:"pins" yaml:"pins"`
+	Together     [][]string          `json:"together" yaml:"together"`
+	Apart        [][]string          `json:"apart" yaml:"apart"`
+	Bench        []string            `json:"bench" yaml:"bench"`
+	Raids        []string            `json:"raids" yaml:"raids"`
+	Availability map[string][]string `json:"availability" yaml:"availability"`
+}
+
+// LoadRules reads a rules file, either in YAML or JSON
+func LoadRules(path string) (Rules, error) {
+	var rules Rules
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Rules resolved to character indices
+type compiledRules struct {
+	pins   []int   // Raid each character is pinned to, -1 if none
+	bench  []bool  // Whether each character must stay on the bench
+	mates  [][]int // Characters that must share the raid of each character
+	rivals [][]int // Characters that must not share the raid of each character
+	groups [][]int // Groups of characters that must play together
+	maxPin int     // Highest raid index a character is pinned to, -1 if none
+
+	priority []int // Characters by decreasing priority when resolving conflicts: pinned, then mains, then alts
+
+	raidNames []string // Names of the raids, if any
+	available []BitSet // Raids each player can attend, nil if the player can attend every raid
+}
+
+// SetRules resolves the names used in the rules and applies them to the problem
+func (p *Problem) SetRules(rules Rules) error {
+	cr := compiledRules{
+		pins:   make([]int, len(p.Roster)),
+		bench:  make([]bool, len(p.Roster)),
+		mates:  make([][]int, len(p.Roster)),
+		rivals: make([][]int, len(p.Roster)),
+		maxPin: -1,
+
+		raidNames: rules.Raids,
+		available: make([]BitSet, len(p.Players)),
+	}
+	for cid := range cr.pins {
+		cr.pins[cid] = -1
+	}
+
+	charIndex := make(map[string]int)
+	for cid, char := range p.Roster {
+		if _, found := charIndex[char.Name]; found {
+			charIndex[char.Name] = -1 // Ambiguous name
+		} else {
+			charIndex[char.Name] = cid
+		}
+	}
+	findChar := func(name string) (int, error) {
+		cid, found := charIndex[name]
+		if !found {
+			return 0, fmt.Errorf("unknown character: %s", name)
+		} else if cid < 0 {
+			return 0, fmt.Errorf("ambiguous character name: %s", name)
+		}
+		return cid, nil
+	}
+
+	for name, raid := range rules.Pins {
+		cid, err := findChar(name)
+		if err != nil {
+			return fmt.Errorf("pins: %w", err)
+		}
+		if raid < 1 || (len(rules.Raids) > 0 && raid > len(rules.Raids)) {
+			return fmt.Errorf("pins: invalid raid for %s: %d", name, raid)
+		}
+		cr.pins[cid] = raid - 1
+		cr.maxPin = Max(cr.maxPin, raid-1)
+	}
+
+	for _, name := range rules.Bench {
+		cid, err := findChar(name)
+		if err != nil {
+			return fmt.Errorf("bench: %w", err)
+		}
+		if cr.pins[cid] >= 0 {
+			return fmt.Errorf("bench: %s is also pinned", name)
+		}
+		cr.bench[cid] = true
+	}
+
+	for _, names := range rules.Together {
+		group := make([]int, 0, len(names))
+		for _, name := range names {
+			cid, err := findChar(name)
+			if err != nil {
+				return fmt.Errorf("together: %w", err)
+			}
+			group = append(group, cid)
+		}
+		for _, cid := range group {
+			for _, oid := range group {
+				if cid != oid {
+					cr.mates[cid] = append(cr.mates[cid], oid)
+				}
+			}
+		}
+		cr.groups = append(cr.groups, group)
+	}
+
+	// Apart entries are player names, or character names if no player matches
+	playerIndex := make(map[string]int)
+	for pid, player := range p.Players {
+		playerIndex[player] = pid
+	}
+	for _, names := range rules.Apart {
+		sets := make([][]int, 0, len(names))
+		for _, name := range names {
+			if pid, found := playerIndex[name]; found {
+				sets = append(sets, p.playerCharacters[pid])
+				continue
+			}
+			cid, err := findChar(name)
+			if err != nil {
+				return fmt.Errorf("apart: %w", err)
+			}
+			sets = append(sets, []int{cid})
+		}
+		for i, set := range sets {
+			for j, other := range sets {
+				if i == j {
+					continue
+				}
+				for _, cid := range set {
+					cr.rivals[cid] = append(cr.rivals[cid], other...)
+				}
+			}
+		}
+	}
+
+	raidIndex := make(map[string]int)
+	for rid, name := range rules.Raids {
+		if _, found := raidIndex[name]; found {
+			return fmt.Errorf("raids: duplicate raid: %s", name)
+		}
+		raidIndex[name] = rid
+	}
+	for player, raids := range rules.Availability {
+		pid, found := playerIndex[player]
+		if !found {
+			return fmt.Errorf("availability: unknown player: %s", player)
+		}
+		cr.available[pid] = MakeBitSet(len(rules.Raids))
+		for _, name := range raids {
+			rid, found := raidIndex[name]
+			if !found {
+				return fmt.Errorf("availability: unknown raid for %s: %s", player, name)
+			}
+			cr.available[pid].Set(rid, true)
+		}
+	}
+
+	for _, pinned := range []bool{true, false} {
+		for _, main := range []bool{true, false} {
+			for cid, char := range p.Roster {
+				if (cr.pins[cid] >= 0) == pinned && char.Main == main {
+					cr.priority = append(cr.priority, cid)
+				}
+			}
+		}
+	}
+
+	p.rules = cr
+	return nil
+}
+
+// Available returns whether the player can attend the raid. Nobody can attend raids beyond the named ones.
+func (p *Problem) Available(player int, rid int) bool {
+	if len(p.rules.raidNames) > 0 && rid >= len(p.rules.raidNames) {
+		return false
+	}
+	return p.rules.available[player] == nil || p.rules.available[player].Get(rid)
+}
+
+// availableRaids returns the number of raids the player can attend among the given number of raids
+func (p *Problem) availableRaids(player int, raidCount int) int {
+	count := 0
+	for rid := 0; rid < raidCount; rid++ {
+		if p.Available(player, rid) {
+			count += 1
+		}
+	}
+	return count
+}
+
+// RaidName returns the name of the raid, or its number if raids are not named
+func (p *Problem) RaidName(rid int) string {
+	if rid < len(p.rules.raidNames) {
+		return p.rules.raidNames[rid]
+	}
+	return fmt.Sprintf("Raid %d", rid+1)
+}
+
+// allowed returns whether the rules allow the character to be moved to the raid (or the bench if negative), given
+// the position of the other characters in the distribution
+func (p *Problem) allowed(dist []int, cid int, rid int) bool {
+	if pin := p.rules.pins[cid]; pin >= 0 && rid != pin {
+		return false
+	}
+	if rid < 0 {
+		return true
+	}
+	if p.rules.bench[cid] || !p.Available(p.Roster[cid].Player, rid) {
+		return false
+	}
+	for _, oid := range p.rules.mates[cid] {
+		if dist[oid] >= 0 && dist[oid] != rid {
+			return false
+		}
+	}
+	for _, oid := range p.rules.rivals[cid] {
+		if dist[oid] == rid {
+			return false
+		}
+	}
+	return true
+}
+
+// pinned returns whether the character is pinned to a raid
+func (p *Problem) pinned(cid int) bool {
+	return p.rules.pins[cid] >= 0
+}
@@ -0,0 +1,46 @@
+package raidopt
+
+import (
+	"math/rand"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// Species smaller than this are merged with the next raid count: tournament selection needs at least 4 individuals
+// to select 2 parents among 3 contestants
+const minSpeciesSize = 4
+
+// Speciator groups individuals by raid count
+type Speciator struct {
+	MinRaids, MaxRaids int
+}
+
+var _ eaopt.Speciator = (*Speciator)(nil)
+
+func (s Speciator) Apply(indis eaopt.Individuals, rng *rand.Rand) ([]eaopt.Individuals, error) {
+	byCount := make([]eaopt.Individuals, s.MaxRaids-s.MinRaids+1)
+	for _, indi := range indis {
+		idx := indi.Genome.(*Genome).RaidCount - s.MinRaids
+		byCount[idx] = append(byCount[idx], indi)
+	}
+
+	var species []eaopt.Individuals
+	var pending eaopt.Individuals
+	for _, group := range byCount {
+		pending = append(pending, group...)
+		if len(pending) >= minSpeciesSize {
+			species = append(species, pending)
+			pending = nil
+		}
+	}
+	if len(species) == 0 {
+		return []eaopt.Individuals{pending}, nil
+	} else if len(pending) > 0 {
+		species[len(species)-1] = append(species[len(species)-1], pending...)
+	}
+	return species, nil
+}
+
+func (s Speciator) Validate() error {
+	return nil
+}
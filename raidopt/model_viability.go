@@ -0,0 +1,273 @@
+package raidopt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation describes a constraint broken by a distribution
+type Violation struct {
+	Raid       int // -1 for the bench
+	Constraint Constraint
+	Observed   float64
+	Min, Max   float64
+	Chars      []int // Characters responsible for the violation, if any
+
+	roster []Character // Used to name the characters
+}
+
+func (v Violation) String() string {
+	var str strings.Builder
+
+	if v.Raid < 0 {
+		str.WriteString("[Bench]   ")
+	} else {
+		fmt.Fprintf(&str, "[Raid %2d] ", v.Raid+1)
+	}
+
+	names := make([]string, len(v.Chars))
+	for i, cid := range v.Chars {
+		if v.roster != nil {
+			names[i] = v.roster[cid].Name
+		} else {
+			names[i] = fmt.Sprintf("#%d", cid)
+		}
+	}
+	chars := strings.Join(names, ", ")
+
+	// Rules on characters name the characters breaking them, bounds on raids print the observed value
+	switch v.Constraint {
+	case ConstraintBenchedMain:
+		fmt.Fprintf(&str, "%s: %s must not be benched", v.Constraint, chars)
+	case ConstraintDuplicatePlayer:
+		fmt.Fprintf(&str, "%s: %s belong to the same player", v.Constraint, chars)
+	case ConstraintRole:
+		fmt.Fprintf(&str, "%s: %s cannot play the assigned role", v.Constraint, chars)
+	case ConstraintPin:
+		if v.Observed > 0 {
+			fmt.Fprintf(&str, "%s: %s is pinned to raid %.0f but plays in raid %.0f", v.Constraint, chars, v.Min, v.Observed)
+		} else {
+			fmt.Fprintf(&str, "%s: %s is pinned to raid %.0f but is benched", v.Constraint, chars, v.Min)
+		}
+	case ConstraintBench:
+		fmt.Fprintf(&str, "%s: %s must stay on the bench", v.Constraint, chars)
+	case ConstraintApart:
+		fmt.Fprintf(&str, "%s: %s must play apart", v.Constraint, chars)
+	case ConstraintTogether:
+		fmt.Fprintf(&str, "%s: %s must play together but are split across %.0f raids", v.Constraint, chars, v.Observed)
+	case ConstraintAvailability:
+		fmt.Fprintf(&str, "%s: the player of %s cannot attend this raid", v.Constraint, chars)
+	default:
+		fmt.Fprintf(&str, "%s: %.4g not in [%.4g, %.4g]", v.Constraint, v.Observed, v.Min, v.Max)
+		if len(names) > 0 {
+			fmt.Fprintf(&str, " (%s)", chars)
+		}
+	}
+
+	return str.String()
+}
+
+func (X *Genome) Viable() bool {
+	return X.problem.Viable(X.Distribution, X.Roles, X.RaidCount)
+}
+
+// Violations returns every constraint broken by the genome
+func (X *Genome) Violations() []Violation {
+	return X.problem.Violations(X.Distribution, X.Roles, X.RaidCount)
+}
+
+func (p *Problem) Viable(distribution []int, roles []Role, size int) bool {
+	return len(p.findViolations(distribution, roles, size, false)) == 0
+}
+
+// Violations returns every constraint broken by the distribution, or nil if it is viable
+func (p *Problem) Violations(distribution []int, roles []Role, size int) []Violation {
+	violations := p.findViolations(distribution, roles, size, true)
+	for i := range violations {
+		violations[i].roster = p.Roster
+	}
+	return violations
+}
+
+// Checks the distribution against every constraint. Unless `all` is set, stops at the first violation found.
+func (p *Problem) findViolations(distribution []int, roles []Role, size int, all bool) (violations []Violation) {
+	type RaidStats struct {
+		RaidComp
+		PlayerIndex BitSet
+		Chars       []int
+	}
+
+	raids := make([]RaidStats, size)
+	for i := range raids {
+		raids[i].PlayerIndex = MakeBitSet(len(p.Players))
+	}
+
+	var benchedMains []int
+	for cid, rid := range distribution {
+		char := p.Roster[cid]
+
+		if pin := p.rules.pins[cid]; pin >= 0 && rid != pin {
+			// Pinned character not in its raid
+			violations = append(violations, Violation{Raid: pin, Constraint: ConstraintPin, Observed: float64(rid + 1), Min: float64(pin + 1), Max: float64(pin + 1), Chars: []int{cid}})
+			if !all {
+				return
+			}
+		}
+
+		if rid < 0 {
+			if char.Main && !p.rules.bench[cid] {
+				// We benched a main
+				if !all {
+					return []Violation{{Raid: -1, Constraint: ConstraintBenchedMain, Observed: 1, Chars: []int{cid}}}
+				}
+				benchedMains = append(benchedMains, cid)
+			}
+			continue
+		}
+
+		raid := &raids[rid]
+
+		if p.rules.bench[cid] {
+			// Character that must stay on the bench
+			violations = append(violations, Violation{Raid: rid, Constraint: ConstraintBench, Observed: 1, Chars: []int{cid}})
+			if !all {
+				return
+			}
+		}
+
+		if !p.Available(char.Player, rid) {
+			// Player unable to attend the raid
+			violations = append(violations, Violation{Raid: rid, Constraint: ConstraintAvailability, Observed: 1, Chars: []int{cid}})
+			if !all {
+				return
+			}
+		}
+
+		for _, oid := range p.rules.rivals[cid] {
+			if oid > cid && distribution[oid] == rid {
+				// Characters that must never play together
+				violations = append(violations, Violation{Raid: rid, Constraint: ConstraintApart, Observed: 2, Max: 1, Chars: []int{cid, oid}})
+				if !all {
+					return
+				}
+			}
+		}
+
+		if !char.HasRole(roles[cid]) {
+			// Character assigned to a role it cannot play
+			violations = append(violations, Violation{Raid: rid, Constraint: ConstraintRole, Observed: 1, Chars: []int{cid}})
+			if !all {
+				return
+			}
+		}
+
+		if raid.PlayerIndex.Get(char.Player) {
+			// Duplicate player in the same raid
+			if !all {
+				return []Violation{{Raid: rid, Constraint: ConstraintDuplicatePlayer, Observed: 2, Max: 1, Chars: []int{cid}}}
+			}
+			var dups []int
+			for _, oid := range raid.Chars {
+				if p.Roster[oid].Player == char.Player {
+					dups = append(dups, oid)
+				}
+			}
+			violations = append(violations, Violation{
+				Raid:       rid,
+				Constraint: ConstraintDuplicatePlayer,
+				Observed:   float64(len(dups) + 1),
+				Max:        1,
+				Chars:      append(dups, cid),
+			})
+		} else {
+			raid.PlayerIndex.Set(char.Player, true)
+		}
+
+		raid.Add(roles[cid])
+		if all {
+			raid.Chars = append(raid.Chars, cid)
+		}
+	}
+
+	if len(benchedMains) > 0 {
+		violations = append(violations, Violation{
+			Raid:       -1,
+			Constraint: ConstraintBenchedMain,
+			Observed:   float64(len(benchedMains)),
+			Chars:      benchedMains,
+		})
+	}
+
+	for _, group := range p.rules.groups {
+		// Characters of the group that are not benched must play in the same raid
+		raidsUsed := make(map[int]bool)
+		var placed []int
+		for _, cid := range group {
+			if rid := distribution[cid]; rid >= 0 {
+				raidsUsed[rid] = true
+				placed = append(placed, cid)
+			}
+		}
+		if len(raidsUsed) > 1 {
+			violations = append(violations, Violation{Raid: distribution[placed[0]], Constraint: ConstraintTogether, Observed: float64(len(raidsUsed)), Min: 1, Max: 1, Chars: placed})
+			if !all {
+				return
+			}
+		}
+	}
+
+	// Returns the characters of the raid having the given role
+	withRole := func(raid *RaidStats, role Role) (chars []int) {
+		for _, cid := range raid.Chars {
+			if roles[cid] == role {
+				chars = append(chars, cid)
+			}
+		}
+		return
+	}
+
+	for rid := range raids {
+		// Validate raid viability
+		raid := &raids[rid]
+		if !p.Constraints.SizeOk(raid.RaidComp) {
+			violations = append(violations, Violation{
+				Raid:       rid,
+				Constraint: ConstraintRaidSize,
+				Observed:   float64(raid.Count),
+				Min:        float64(p.Constraints.MinRaidSize),
+				Max:        float64(p.Constraints.MaxRaidSize),
+			})
+			if !all {
+				return
+			}
+		}
+		if !p.Constraints.TanksOk(raid.RaidComp) {
+			violations = append(violations, Violation{
+				Raid:       rid,
+				Constraint: ConstraintTankCount,
+				Observed:   float64(raid.Tanks),
+				Min:        float64(p.Constraints.MinTanks),
+				Max:        float64(p.Constraints.MaxTanks),
+				Chars:      withRole(raid, Tank),
+			})
+			if !all {
+				return
+			}
+		}
+		if !p.Constraints.HealerRatioOk(raid.RaidComp) {
+			violations = append(violations, Violation{
+				Raid:       rid,
+				Constraint: ConstraintHealerRatio,
+				Observed:   raid.HealerRatio(),
+				Min:        p.Constraints.HealerMinRatio,
+				Max:        p.Constraints.HealerMaxRatio,
+				Chars:      withRole(raid, Healer),
+			})
+			if !all {
+				return
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,269 @@
+package raidopt
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// An individual of the NSGA-II populations, along with the value of each objective
+type nsgaIndividual struct {
+	genome     *Genome
+	objectives []float64
+	total      float64 // Weighted fitness, only used to report progress and order the front
+	rank       int     // Index of the non-dominated front of the individual
+	crowding   float64
+}
+
+func makeNSGAIndividual(X *Genome) *nsgaIndividual {
+	fb := X.Fitness()
+	indi := &nsgaIndividual{genome: X, objectives: make([]float64, len(fb)), total: fb.Total()}
+	X.setFitness(indi.total)
+	for i, t := range fb {
+		indi.objectives[i] = t.Value
+	}
+	return indi
+}
+
+// Returns whether the individual is at least as good as the other one for every objective and better for one
+func (indi *nsgaIndividual) dominates(other *nsgaIndividual) bool {
+	better := false
+	for i, v := range indi.objectives {
+		if v > other.objectives[i] {
+			return false
+		} else if v < other.objectives[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// Returns whether the individual wins a binary tournament against the other one
+func (indi *nsgaIndividual) beats(other *nsgaIndividual) bool {
+	if indi.rank != other.rank {
+		return indi.rank < other.rank
+	}
+	return indi.crowding > other.crowding
+}
+
+// Sorts the individuals into non-dominated fronts, best front first, setting the rank of each individual. Individuals
+// are visited in lexicographic order of their objectives, so that none is dominated by a later one, and each joins the
+// first front having no member dominating it. Fronts are found by binary search, as an individual dominated by a
+// member of a front is also dominated by a member of every front before it.
+func nonDominatedSort(indis []*nsgaIndividual) [][]*nsgaIndividual {
+	sorted := make([]*nsgaIndividual, len(indis))
+	copy(sorted, indis)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i].objectives, sorted[j].objectives
+		for o := range a {
+			if a[o] != b[o] {
+				return a[o] < b[o]
+			}
+		}
+		return false
+	})
+
+	var fronts [][]*nsgaIndividual
+	for _, indi := range sorted {
+		rank := sort.Search(len(fronts), func(k int) bool {
+			front := fronts[k]
+			// Later members are the closest to the individual, and the most likely to dominate it
+			for i := len(front) - 1; i >= 0; i-- {
+				if front[i].dominates(indi) {
+					return false
+				}
+			}
+			return true
+		})
+		if rank == len(fronts) {
+			fronts = append(fronts, nil)
+		}
+		indi.rank = rank
+		fronts[rank] = append(fronts[rank], indi)
+	}
+
+	return fronts
+}
+
+// Computes the crowding distance of the individuals of a front
+func assignCrowding(front []*nsgaIndividual) {
+	for _, indi := range front {
+		indi.crowding = 0
+	}
+	if len(front) == 0 {
+		return
+	}
+
+	for o := range front[0].objectives {
+		sort.Slice(front, func(i, j int) bool {
+			return front[i].objectives[o] < front[j].objectives[o]
+		})
+		min, max := front[0].objectives[o], front[len(front)-1].objectives[o]
+		front[0].crowding, front[len(front)-1].crowding = math.Inf(1), math.Inf(1)
+		if max == min {
+			continue
+		}
+		for i := 1; i < len(front)-1; i++ {
+			front[i].crowding += (front[i+1].objectives[o] - front[i-1].objectives[o]) / (max - min)
+		}
+	}
+}
+
+// A single NSGA-II population
+type nsgaPopulation struct {
+	problem *Problem
+	rng     *rand.Rand
+	indis   []*nsgaIndividual
+}
+
+func (pop *nsgaPopulation) tournament() *nsgaIndividual {
+	a, b := pop.indis[pop.rng.Intn(len(pop.indis))], pop.indis[pop.rng.Intn(len(pop.indis))]
+	if b.beats(a) {
+		return b
+	}
+	return a
+}
+
+// Breeds as many offspring as there are individuals, then keeps the best half of parents and offspring
+func (pop *nsgaPopulation) evolve() {
+	size := len(pop.indis)
+	offspring := make([]*nsgaIndividual, 0, size)
+	for len(offspring) < size {
+		X := pop.tournament().genome.Clone().(*Genome)
+		Y := pop.tournament().genome.Clone().(*Genome)
+		X.Crossover(Y, pop.rng)
+		X.Mutate(pop.rng)
+		Y.Mutate(pop.rng)
+		offspring = append(offspring, makeNSGAIndividual(X), makeNSGAIndividual(Y))
+	}
+
+	combined := make([]*nsgaIndividual, 0, 2*size)
+	combined = append(append(combined, pop.indis...), offspring[:size]...)
+	next := make([]*nsgaIndividual, 0, size)
+	for _, front := range nonDominatedSort(combined) {
+		assignCrowding(front)
+		if len(next)+len(front) > size {
+			sort.Slice(front, func(i, j int) bool {
+				return front[i].crowding > front[j].crowding
+			})
+			front = front[:size-len(next)]
+		}
+		next = append(next, front...)
+		if len(next) == size {
+			break
+		}
+	}
+	pop.indis = next
+}
+
+// Runs the NSGA-II model: every population evolves independently, then their first fronts are merged. Returns the
+// non-dominated splits, with distinct objective values, ordered by weighted fitness.
+func (o *Optimizer) runNSGA2(ctx context.Context) ([]*Genome, error) {
+	p := o.Problem
+	switch {
+	case o.Config.NPops == 0:
+		return nil, errors.New("NPops has to be strictly higher than 0")
+	case o.Config.PopSize == 0:
+		return nil, errors.New("PopSize has to be strictly higher than 0")
+	case o.Config.NGenerations == 0:
+		return nil, errors.New("NGenerations has to be strictly higher than 0")
+	}
+
+	pops := make([]*nsgaPopulation, o.Config.NPops)
+	for i := range pops {
+		pop := &nsgaPopulation{problem: p, rng: rand.New(rand.NewSource(time.Now().UnixNano() + int64(i)))}
+		p.operatorSelector(pop.rng)
+		for j := uint(0); j < o.Config.PopSize; j++ {
+			pop.indis = append(pop.indis, makeNSGAIndividual(p.MakeRaid(pop.rng)))
+		}
+		for _, front := range nonDominatedSort(pop.indis) {
+			assignCrowding(front)
+		}
+		pops[i] = pop
+	}
+
+	p.logf("Starting...")
+	for gen := uint(1); gen <= o.Config.NGenerations && ctx.Err() == nil; gen++ {
+		var wg sync.WaitGroup
+		for _, pop := range pops {
+			wg.Add(1)
+			go func(pop *nsgaPopulation) {
+				defer wg.Done()
+				pop.evolve()
+			}(pop)
+		}
+		wg.Wait()
+
+		if o.OnProgress != nil {
+			best := math.Inf(1)
+			for _, pop := range pops {
+				for _, indi := range pop.indis {
+					best = math.Min(best, indi.total)
+				}
+			}
+			o.OnProgress(Progress{Generation: gen, Generations: o.Config.NGenerations, Best: best})
+		}
+
+		stats := make([]PopulationStats, len(pops))
+		for i, pop := range pops {
+			genomes := make([]*Genome, len(pop.indis))
+			fitness := make([]float64, len(pop.indis))
+			for j, indi := range pop.indis {
+				genomes[j], fitness[j] = indi.genome, indi.total
+			}
+			o.updateRaidCountBest(genomes, fitness)
+			if o.OnGeneration != nil {
+				stats[i] = populationStats(gen, i, genomes, fitness)
+			}
+		}
+		if o.OnGeneration != nil {
+			p.attachOperatorStats(stats)
+			o.OnGeneration(stats)
+		}
+	}
+
+	var candidates []*nsgaIndividual
+	for _, pop := range pops {
+		for _, indi := range pop.indis {
+			if indi.rank == 0 {
+				candidates = append(candidates, indi)
+			}
+		}
+	}
+
+	fronts := nonDominatedSort(candidates)
+	if len(fronts) == 0 {
+		return nil, nil
+	}
+	front := fronts[0]
+	sort.Slice(front, func(i, j int) bool {
+		return front[i].total < front[j].total
+	})
+
+	var best []*Genome
+	var kept []*nsgaIndividual
+	for _, indi := range front {
+		duplicate := false
+		for _, other := range kept {
+			duplicate = duplicate || sameObjectives(indi, other)
+		}
+		if !duplicate {
+			kept = append(kept, indi)
+			best = append(best, indi.genome)
+		}
+	}
+	return best, nil
+}
+
+func sameObjectives(a, b *nsgaIndividual) bool {
+	for i, v := range a.objectives {
+		if v != b.objectives[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package raidopt
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Objective is a criterion of the fitness, lower is better. The fitness minimized by the genetic algorithm is the
+// weighted sum of the objectives.
+type Objective struct {
+	Name     string
+	Weight   float64 // Default weight, may be overridden by the objectives config
+	Evaluate func(X *Genome) float64
+}
+
+// Objectives available with every strategy, strategies provide their own on top of them
+var objectives = make(map[string]Objective)
+
+// RegisterObjective makes an objective available to every problem
+func RegisterObjective(o Objective) {
+	if _, found := objectives[o.Name]; found {
+		panic(fmt.Sprintf("duplicate objective: %s", o.Name))
+	}
+	objectives[o.Name] = o
+}
+
+func init() {
+	RegisterObjective(Objective{Name: "buffs", Weight: 0.02, Evaluate: missingBuffs})
+	RegisterObjective(Objective{Name: "off_role", Weight: 0.01, Evaluate: offRole})
+	RegisterObjective(Objective{Name: "size_balance", Weight: 0.000005, Evaluate: sizeImbalance})
+	RegisterObjective(Objective{Name: "movement", Weight: 50, Evaluate: movement})
+}
+
+// ObjectiveConfig overrides the default weights of the objectives and the raid buffs tracked by the buffs objective:
+//
+//	weights:           # Objectives not listed keep their default weight, 0 disables an objective
+//	  armor: 1000
+//	  buffs: 0.02
+//	buffs:             # Raid buffs and the classes providing them
+//	  - name: Arcane Intellect
+//	    classes: [mage]
+type ObjectiveConfig struct {
+	Weights map[string]float64 `json:"weights" yaml:"weights"`
+	Buffs   []Buff             `json:"buffs" yaml:"buffs"`
+}
+
+type Buff struct {
+	Name    string   `json:"name" yaml:"name"`
+	Classes []string `json:"classes" yaml:"classes"`
+}
+
+var DefaultBuffs = []Buff{
+	{Name: "Arcane Intellect", Classes: []string{"mage"}},
+	{Name: "Power Word: Fortitude", Classes: []string{"priest"}},
+	{Name: "Battle Shout", Classes: []string{"warrior"}},
+	{Name: "Chaos Brand", Classes: []string{"demonhunter"}},
+	{Name: "Mystic Touch", Classes: []string{"monk"}},
+}
+
+// LoadObjectives reads an objectives file, either in YAML or JSON
+func LoadObjectives(path string) (ObjectiveConfig, error) {
+	var config ObjectiveConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Validate checks that every weighted objective exists with the given strategy and that the buffs are valid
+func (config ObjectiveConfig) Validate(strategy Strategy) error {
+	_, err := config.compile(strategy)
+	if err != nil {
+		return err
+	}
+	_, err = config.compileBuffs()
+	return err
+}
+
+// An objective along with its effective weight
+type weightedObjective struct {
+	Objective
+	weight float64
+}
+
+// Resolves the weight of every objective, in name order. Objectives with a zero weight are left out.
+func (config ObjectiveConfig) compile(strategy Strategy) ([]weightedObjective, error) {
+	available := make(map[string]Objective)
+	for name, o := range objectives {
+		available[name] = o
+	}
+	for _, o := range strategy.Objectives() {
+		available[o.Name] = o
+	}
+
+	for name, weight := range config.Weights {
+		if _, found := available[name]; !found {
+			return nil, fmt.Errorf("unknown objective with the %s strategy: %s", strategy, name)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("negative weight for objective %s: %g", name, weight)
+		}
+	}
+
+	var compiled []weightedObjective
+	for name, o := range available {
+		weight := o.Weight
+		if w, found := config.Weights[name]; found {
+			weight = w
+		}
+		if weight > 0 {
+			compiled = append(compiled, weightedObjective{o, weight})
+		}
+	}
+	sort.Slice(compiled, func(i, j int) bool {
+		return compiled[i].Name < compiled[j].Name
+	})
+	return compiled, nil
+}
+
+// Returns the bitmask of the buffs provided by each class
+func (config ObjectiveConfig) compileBuffs() ([]uint64, error) {
+	buffs := config.Buffs
+	if buffs == nil {
+		buffs = DefaultBuffs
+	}
+	if len(buffs) > 64 {
+		return nil, fmt.Errorf("too many buffs: %d", len(buffs))
+	}
+
+	classBuffs := make([]uint64, len(classNames))
+	for i, buff := range buffs {
+		for _, name := range buff.Classes {
+			class, err := ParseClass(name)
+			if err != nil {
+				return nil, fmt.Errorf("buff %s: %w", buff.Name, err)
+			}
+			classBuffs[class] |= 1 << i
+		}
+	}
+	return classBuffs, nil
+}
+
+// Prepares the objectives of the problem, must be called after the strategy has been set
+func (p *Problem) compileObjectives() (err error) {
+	if p.objectives, err = p.Objectives.compile(p.Strategy); err != nil {
+		return err
+	}
+	if p.previous == nil {
+		// Nothing can move without a previous split, the movement objective would be a constant
+		objectives := p.objectives[:0]
+		for _, o := range p.objectives {
+			if o.Name != "movement" {
+				objectives = append(objectives, o)
+			}
+		}
+		p.objectives = objectives
+	}
+	if p.classBuffs, err = p.Objectives.compileBuffs(); err != nil {
+		return err
+	}
+
+	buffCount := len(p.Objectives.Buffs)
+	if p.Objectives.Buffs == nil {
+		buffCount = len(DefaultBuffs)
+	}
+	p.allBuffs = 1<<buffCount - 1
+	return nil
+}
+
+// Average number of raid buffs missing per raid
+func missingBuffs(X *Genome) float64 {
+	p := X.problem
+	raidBuffs := make([]uint64, X.RaidCount)
+	for cid, rid := range X.Distribution {
+		if rid >= 0 {
+			raidBuffs[rid] |= p.classBuffs[p.Roster[cid].Class]
+		}
+	}
+
+	var missing int
+	for _, buffs := range raidBuffs {
+		for b := p.allBuffs &^ buffs; b != 0; b &= b - 1 {
+			missing += 1
+		}
+	}
+	return float64(missing) / float64(X.RaidCount)
+}
+
+// Share of the roster playing another role than their preferred one
+func offRole(X *Genome) float64 {
+	p := X.problem
+	var count int
+	for cid, rid := range X.Distribution {
+		if rid >= 0 && X.Roles[cid] != p.Roster[cid].Role {
+			count += 1
+		}
+	}
+	return float64(count) / float64(len(p.Roster))
+}
+
+// Size difference between the largest and the smallest raid
+func sizeImbalance(X *Genome) float64 {
+	count := make([]int, X.RaidCount)
+	for _, rid := range X.Distribution {
+		if rid >= 0 {
+			count[rid] += 1
+		}
+	}
+
+	min, max := len(X.Distribution), 0
+	for _, c := range count {
+		min, max = Min(min, c), Max(max, c)
+	}
+	return float64(max - min)
+}
+
+// Characters moved from the raid they played in the previous split
+func movement(X *Genome) float64 {
+	return float64(X.problem.moved(X))
+}
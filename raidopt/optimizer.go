@@ -0,0 +1,193 @@
+package raidopt
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"github.com/MaxHalford/eaopt"
+)
+
+// OptimizerConfig holds the parameters of the genetic algorithm
+type OptimizerConfig struct {
+	NPops        uint   `json:"npops"`   // Number of populations
+	PopSize      uint   `json:"popsize"` // Number of individuals in each population
+	NGenerations uint   `json:"gen"`     // Number of generations
+	HofSize      uint   `json:"hof"`     // Number of best genomes returned
+	Model        string `json:"model"`   // EA model: default, mutonly, mutonly-nonstrict or nsga2
+
+	// Number of diverse genomes returned instead of the best ones, 0 to disable, and their minimum distance (see
+	// Genome.Distance). Not used by the nsga2 model.
+	TopK        uint `json:"top_k"`
+	MinDistance int  `json:"min_distance"`
+}
+
+var DefaultOptimizerConfig = OptimizerConfig{
+	NPops:        12,
+	PopSize:      3000,
+	NGenerations: 2000,
+	HofSize:      1,
+	Model:        "default",
+	MinDistance:  100,
+}
+
+// ParseModel returns the EA model with the given name. The nsga2 model is run by the optimizer itself and has no
+// eaopt counterpart, a nil model is returned for it.
+func ParseModel(name string) (eaopt.Model, error) {
+	switch name {
+	case "nsga2":
+		return nil, nil
+	case "mutonly":
+		return eaopt.ModMutationOnly{Strict: true}, nil
+	case "mutonly-nonstrict":
+		return eaopt.ModMutationOnly{Strict: false}, nil
+	case "default":
+		return eaopt.ModGenerational{Selector: eaopt.SelTournament{NContestants: 3}, MutRate: 1, CrossRate: 1}, nil
+	}
+
+	return nil, fmt.Errorf("unknown model: %s", name)
+}
+
+// Progress describes the state of a running optimization
+type Progress struct {
+	Generation  uint    `json:"generation"`  // Number of generations completed
+	Generations uint    `json:"generations"` // Total number of generations
+	Best        float64 `json:"best"`        // Best fitness found so far
+}
+
+// Optimizer runs the genetic algorithm on a problem
+type Optimizer struct {
+	Problem      *Problem
+	Config       OptimizerConfig
+	OnProgress   func(Progress)          // Called after each generation, may be nil
+	OnGeneration func([]PopulationStats) // Called after each generation with the statistics of every population, may be nil
+
+	// Best genome found for each raid count, along with its fitness
+	raidCountBest    map[int]*Genome
+	raidCountFitness map[int]float64
+
+	diverse *diverseArchive // Diverse genomes found, if TopK is set
+}
+
+func NewOptimizer(p *Problem, config OptimizerConfig) *Optimizer {
+	return &Optimizer{Problem: p, Config: config}
+}
+
+// Updates the best genome of each raid count with the genomes of a population
+func (o *Optimizer) updateRaidCountBest(genomes []*Genome, fitness []float64) {
+	if o.raidCountBest == nil {
+		o.raidCountBest = make(map[int]*Genome)
+		o.raidCountFitness = make(map[int]float64)
+	}
+	for i, X := range genomes {
+		if best, found := o.raidCountFitness[X.RaidCount]; !found || fitness[i] < best {
+			o.raidCountBest[X.RaidCount] = X.Clone().(*Genome)
+			o.raidCountFitness[X.RaidCount] = fitness[i]
+		}
+	}
+}
+
+// BestByRaidCount returns the best genome found for each raid count, by increasing raid count
+func (o *Optimizer) BestByRaidCount() []*Genome {
+	best := make([]*Genome, 0, len(o.raidCountBest))
+	for raidCount := o.Problem.minRaids; raidCount <= o.Problem.maxRaids; raidCount++ {
+		if X, found := o.raidCountBest[raidCount]; found {
+			best = append(best, X)
+		}
+	}
+	return best
+}
+
+// Run prepares the problem, then evolves populations until the configured number of generations is reached or the
+// context is cancelled. Returns the best genomes found, best first. With the nsga2 model, returns the Pareto front of
+// the objectives instead, ordered by weighted fitness. If TopK is set, returns the best genomes that are at least
+// MinDistance apart instead of the best ones. The best genome of each raid count is then available from
+// BestByRaidCount.
+func (o *Optimizer) Run(ctx context.Context) ([]*Genome, error) {
+	p := o.Problem
+	if err := p.Prepare(); err != nil {
+		return nil, err
+	}
+
+	model, err := ParseModel(o.Config.Model)
+	if err != nil {
+		return nil, err
+	} else if model == nil {
+		return o.runNSGA2(ctx)
+	}
+
+	config := eaopt.GAConfig{
+		NPops:        o.Config.NPops,
+		PopSize:      o.Config.PopSize,
+		NGenerations: o.Config.NGenerations,
+		HofSize:      o.Config.HofSize,
+		Model:        model,
+		MigFrequency: o.Config.NGenerations / 5,
+		Migrator:     eaopt.MigRing{NMigrants: o.Config.PopSize / 4},
+	}
+	if config.MigFrequency == 0 {
+		config.MigFrequency = 1
+	}
+
+	if p.minRaids != p.maxRaids {
+		config.Speciator = Speciator{MinRaids: p.minRaids, MaxRaids: p.maxRaids}
+	}
+
+	if o.Config.TopK > 0 {
+		o.diverse = newDiverseArchive(int(o.Config.TopK), o.Config.MinDistance)
+	}
+
+	config.Callback = func(ga *eaopt.GA) {
+		if o.OnProgress != nil {
+			o.OnProgress(Progress{Generation: ga.Generations, Generations: ga.NGenerations, Best: ga.HallOfFame[0].Fitness})
+		}
+
+		stats := make([]PopulationStats, len(ga.Populations))
+		for i, pop := range ga.Populations {
+			genomes := make([]*Genome, len(pop.Individuals))
+			fitness := make([]float64, len(pop.Individuals))
+			for j, indi := range pop.Individuals {
+				genomes[j], fitness[j] = indi.Genome.(*Genome), indi.Fitness
+			}
+			o.updateRaidCountBest(genomes, fitness)
+			if o.diverse != nil {
+				o.diverse.offerPopulation(genomes, fitness)
+			}
+			if o.OnGeneration != nil {
+				stats[i] = populationStats(ga.Generations, i, genomes, fitness)
+			}
+		}
+		if o.OnGeneration != nil {
+			p.attachOperatorStats(stats)
+			o.OnGeneration(stats)
+		}
+	}
+
+	config.EarlyStop = func(ga *eaopt.GA) bool {
+		return ctx.Err() != nil
+	}
+
+	ga, err := config.NewGA()
+	if err != nil {
+		return nil, err
+	}
+
+	p.logf("Starting...")
+	err = ga.Minimize(func(rng *rand.Rand) eaopt.Genome {
+		p.operatorSelector(rng)
+		return p.MakeRaid(rng)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if o.diverse != nil {
+		return o.diverse.genomes, nil
+	}
+
+	best := make([]*Genome, len(ga.HallOfFame))
+	for i, indi := range ga.HallOfFame {
+		best[i] = indi.Genome.(*Genome)
+	}
+	return best, nil
+}
@@ -0,0 +1,248 @@
+package raidopt
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SplitReport is the machine-readable description of a split
+type SplitReport struct {
+	RaidCount int            `json:"raid_count"`
+	RaidNames []string       `json:"raid_names"`
+	Raids     [][]ReportChar `json:"raids"`
+	Bench     []ReportChar   `json:"bench"`
+	Fitness   ReportFitness  `json:"fitness"`
+	Stats     []StrategyStat `json:"stats"`
+	Moves     []ReportMove   `json:"moves,omitempty"` // Only when a previous split is set
+}
+
+type ReportChar struct {
+	Player string `json:"player"`
+	Name   string `json:"name"`
+	Class  string `json:"class"`
+	Role   string `json:"role"`
+	Main   bool   `json:"main"`
+}
+
+type ReportFitness struct {
+	Total float64      `json:"total"`
+	Terms []ReportTerm `json:"terms"`
+}
+
+type ReportTerm struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// MakeReport builds the report of a genome. Raids and bench list characters in roster order.
+func MakeReport(X *Genome) SplitReport {
+	p := X.problem
+	report := SplitReport{
+		RaidCount: X.RaidCount,
+		RaidNames: make([]string, X.RaidCount),
+		Raids:     make([][]ReportChar, X.RaidCount),
+		Bench:     make([]ReportChar, 0),
+		Stats:     p.Strategy.Stats(X),
+		Moves:     p.Moves(X),
+	}
+
+	for rid := range report.Raids {
+		report.RaidNames[rid] = p.RaidName(rid)
+		report.Raids[rid] = make([]ReportChar, 0)
+	}
+
+	for cid, rid := range X.Distribution {
+		char := p.Roster[cid]
+		rc := ReportChar{
+			Player: p.Players[char.Player],
+			Name:   char.Name,
+			Class:  char.Class.String(),
+			Role:   char.Role.String(),
+			Main:   char.Main,
+		}
+
+		if rid < 0 {
+			report.Bench = append(report.Bench, rc)
+		} else {
+			rc.Role = X.Roles[cid].String()
+			report.Raids[rid] = append(report.Raids[rid], rc)
+		}
+	}
+
+	fb := X.Fitness()
+	report.Fitness = ReportFitness{Total: fb.Total(), Terms: make([]ReportTerm, len(fb))}
+	for i, t := range fb {
+		report.Fitness.Terms[i] = ReportTerm{Name: t.Name, Value: t.Value, Weight: t.Weight, Contribution: t.Contribution()}
+	}
+
+	return report
+}
+
+// WriteSplit writes the genome in the given output format
+func WriteSplit(w io.Writer, X *Genome, format string) error {
+	switch format {
+	case "text":
+		return PrintRaid(w, X)
+	case "json":
+		return WriteJSONReport(w, MakeReport(X))
+	case "csv":
+		return WriteCSVReport(w, MakeReport(X))
+	}
+
+	return fmt.Errorf("unknown output format: %s", format)
+}
+
+// WriteFront writes a set of splits, such as a Pareto front: in text, a summary of the objectives of every split
+// followed by the splits themselves, in JSON, an array of reports and in CSV, the summary table followed by the
+// characters of every split
+func WriteFront(w io.Writer, front []*Genome, format string) error {
+	return writeSplits(w, front, fmt.Sprintf("%d non-dominated splits", len(front)), func(i int, X *Genome) string {
+		return fmt.Sprintf("Split %d", i+1)
+	}, format)
+}
+
+// WriteByRaidCount writes the best split of each raid count, as returned by BestByRaidCount, in the same formats as
+// WriteFront
+func WriteByRaidCount(w io.Writer, best []*Genome, format string) error {
+	return writeSplits(w, best, "Best split of each raid count", func(i int, X *Genome) string {
+		return fmt.Sprintf("%d raids", X.RaidCount)
+	}, format)
+}
+
+// WriteDiverse writes diverse splits, as returned with TopK set, in the same formats as WriteFront. In text, the
+// heading of each split gives its distance to the best one.
+func WriteDiverse(w io.Writer, splits []*Genome, format string) error {
+	return writeSplits(w, splits, fmt.Sprintf("%d diverse splits", len(splits)), func(i int, X *Genome) string {
+		if i == 0 {
+			return "Split 1"
+		}
+		return fmt.Sprintf("Split %d, at distance %d from split 1", i+1, X.Distance(splits[0]))
+	}, format)
+}
+
+// Writes a set of splits with a summary, each split being introduced by its heading in text
+func writeSplits(w io.Writer, splits []*Genome, title string, heading func(int, *Genome) string, format string) error {
+	reports := make([]SplitReport, len(splits))
+	for i, X := range splits {
+		reports[i] = MakeReport(X)
+	}
+
+	switch format {
+	case "text":
+		printSummary(w, title, reports)
+		for i, X := range splits {
+			fmt.Fprintf(w, "\n=== %s ===\n", heading(i, X))
+			if err := PrintRaid(w, X); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	case "csv":
+		return writeCSVSplits(w, reports)
+	}
+
+	return fmt.Errorf("unknown output format: %s", format)
+}
+
+// Writes one row per split with the value of each objective, then the characters of every split as in
+// WriteCSVReport, with the split as first column
+func writeCSVSplits(w io.Writer, reports []SplitReport) error {
+	writer := csv.NewWriter(w)
+	if len(reports) > 0 {
+		header := []string{"split", "raid_count", "total"}
+		for _, t := range reports[0].Fitness.Terms {
+			header = append(header, t.Name)
+		}
+		writer.Write(header)
+	}
+	for i, report := range reports {
+		row := []string{strconv.Itoa(i + 1), strconv.Itoa(report.RaidCount), strconv.FormatFloat(report.Fitness.Total, 'f', -1, 64)}
+		for _, t := range report.Fitness.Terms {
+			row = append(row, strconv.FormatFloat(t.Value, 'f', -1, 64))
+		}
+		writer.Write(row)
+	}
+
+	writer.Write(nil)
+	writer.Write([]string{"split", "raid", "player", "name", "class", "role", "main", "raid_name"})
+	for i, report := range reports {
+		writeCSVChars(writer, report, strconv.Itoa(i+1))
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Writes one row per character of the report, raid by raid then the bench, each row starting with the given columns
+func writeCSVChars(writer *csv.Writer, report SplitReport, prefix ...string) {
+	writeChars := func(raid string, name string, chars []ReportChar) {
+		for _, c := range chars {
+			writer.Write(append(append([]string{}, prefix...), raid, c.Player, c.Name, c.Class, c.Role, strconv.FormatBool(c.Main), name))
+		}
+	}
+
+	for rid, chars := range report.Raids {
+		writeChars(strconv.Itoa(rid+1), report.RaidNames[rid], chars)
+	}
+	writeChars("bench", "", report.Bench)
+}
+
+func WriteJSONReport(w io.Writer, report SplitReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteCSVReport writes the report as tables separated by blank lines, each starting with its header row: the
+// characters (with "bench" as the raid of benched characters), the fitness components, the strategy stats and, when
+// a previous split is set, the moves.
+func WriteCSVReport(w io.Writer, report SplitReport) error {
+	writer := csv.NewWriter(w)
+	formatFloat := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	writer.Write([]string{"raid", "player", "name", "class", "role", "main", "raid_name"})
+	writeCSVChars(writer, report)
+
+	writer.Write(nil)
+	writer.Write([]string{"component", "value", "weight", "contribution"})
+	writer.Write([]string{"raid_count", strconv.Itoa(report.RaidCount), "", ""})
+	writer.Write([]string{"total", formatFloat(report.Fitness.Total), "", ""})
+	for _, t := range report.Fitness.Terms {
+		writer.Write([]string{t.Name, formatFloat(t.Value), formatFloat(t.Weight), formatFloat(t.Contribution)})
+	}
+
+	writer.Write(nil)
+	writer.Write([]string{"raid", "group", "receivers", "traders", "ratio", "target"})
+	for _, stat := range report.Stats {
+		writer.Write([]string{
+			strconv.Itoa(stat.Raid),
+			stat.Group,
+			strconv.Itoa(stat.Receivers),
+			strconv.Itoa(stat.Traders),
+			formatFloat(stat.Ratio),
+			formatFloat(stat.Target),
+		})
+	}
+
+	if report.Moves != nil {
+		writer.Write(nil)
+		writer.Write([]string{"player", "name", "from", "to"})
+		for _, move := range report.Moves {
+			writer.Write([]string{move.Player, move.Name, move.From, move.To})
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,258 @@
+package raidopt
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Share of the initial population started from the previous split
+const seedRate = 20
+
+// A previous split, resolved against the current roster
+type previousSplit struct {
+	raidCount int
+	raids     []int        // Previous raid of each character, -1 if benched, noPrevious if absent
+	roles     []Role       // Previous role of each character
+	names     []string     // Previous raid names
+	removed   []ReportChar // Characters of the previous split that are no longer in the roster
+	removedAt []int        // Previous raid of the removed characters
+}
+
+const noPrevious = -2
+
+// ReportMove is a character whose placement changed since the previous split
+type ReportMove struct {
+	Player string `json:"player"`
+	Name   string `json:"name"`
+	From   string `json:"from"` // Empty if the character was not in the previous split
+	To     string `json:"to"`   // Empty if the character is no longer in the roster
+}
+
+// LoadSplit reads a split written by WriteSplit, either in JSON or in CSV depending on the file extension
+func LoadSplit(path string) (SplitReport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return SplitReport{}, err
+	}
+	defer f.Close()
+
+	var report SplitReport
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		err = json.NewDecoder(f).Decode(&report)
+	} else {
+		report, err = ReadCSVSplit(f)
+	}
+	if err != nil {
+		return report, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
+
+// ReadCSVSplit reads the characters table of a split written by WriteCSVReport. Other tables are ignored.
+func ReadCSVSplit(r io.Reader) (SplitReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var report SplitReport
+	header, err := reader.Read()
+	if err != nil {
+		return report, err
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[column] = i
+	}
+	for _, column := range []string{"raid", "player", "name", "role"} {
+		if _, found := columns[column]; !found {
+			return report, fmt.Errorf("missing column: %s", column)
+		}
+	}
+	get := func(values []string, column string) string {
+		if idx, found := columns[column]; found && idx < len(values) {
+			return values[idx]
+		}
+		return ""
+	}
+
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) || (err == nil && values[0] == "component") {
+			break // End of the characters table
+		} else if err != nil {
+			return report, err
+		}
+
+		char := ReportChar{
+			Player: get(values, "player"),
+			Name:   get(values, "name"),
+			Class:  get(values, "class"),
+			Role:   get(values, "role"),
+			Main:   get(values, "main") == "true",
+		}
+
+		if raid := get(values, "raid"); raid == "bench" {
+			report.Bench = append(report.Bench, char)
+		} else {
+			rid, err := strconv.Atoi(raid)
+			if err != nil || rid < 1 {
+				row, _ := reader.FieldPos(0)
+				return report, fmt.Errorf("row %d: invalid raid: %q", row, raid)
+			}
+			for len(report.Raids) < rid {
+				report.Raids = append(report.Raids, nil)
+				report.RaidNames = append(report.RaidNames, "")
+			}
+			report.Raids[rid-1] = append(report.Raids[rid-1], char)
+			report.RaidNames[rid-1] = get(values, "raid_name")
+		}
+	}
+
+	report.RaidCount = len(report.Raids)
+	return report, nil
+}
+
+// SetPrevious sets the split the optimization starts from. Characters are matched by player and name, characters
+// that joined the roster since are considered benched.
+func (p *Problem) SetPrevious(report SplitReport) error {
+	if report.RaidCount != len(report.Raids) {
+		return fmt.Errorf("previous split has %d raids but lists %d", report.RaidCount, len(report.Raids))
+	}
+
+	prev := &previousSplit{
+		raidCount: report.RaidCount,
+		raids:     make([]int, len(p.Roster)),
+		roles:     make([]Role, len(p.Roster)),
+		names:     report.RaidNames,
+	}
+
+	charIndex := make(map[[2]string]int)
+	for cid, char := range p.Roster {
+		charIndex[[2]string{p.Players[char.Player], char.Name}] = cid
+		prev.raids[cid] = noPrevious
+		prev.roles[cid] = char.Role
+	}
+
+	add := func(rid int, rc ReportChar) {
+		cid, found := charIndex[[2]string{rc.Player, rc.Name}]
+		if !found {
+			prev.removed = append(prev.removed, rc)
+			prev.removedAt = append(prev.removedAt, rid)
+			return
+		}
+		prev.raids[cid] = rid
+		if role, err := ParseRole(rc.Role); err == nil && p.Roster[cid].HasRole(role) {
+			prev.roles[cid] = role
+		}
+	}
+
+	for rid, chars := range report.Raids {
+		for _, rc := range chars {
+			add(rid, rc)
+		}
+	}
+	for _, rc := range report.Bench {
+		add(-1, rc)
+	}
+
+	p.previous = prev
+	return nil
+}
+
+// Builds a viable genome from the previous split, repaired to follow the current roster and rules. Returns nil if the
+// previous split cannot be repaired.
+func (p *Problem) makeSeed() *Genome {
+	prev := p.previous
+	feasible := false
+	for _, raidCount := range p.raidCounts {
+		feasible = feasible || raidCount == prev.raidCount
+	}
+	if !feasible {
+		p.logf("Previous split has %d raids, which is not feasible, starting from random splits", prev.raidCount)
+		return nil
+	}
+
+	X := &Genome{
+		problem:      p,
+		RaidCount:    prev.raidCount,
+		Distribution: make([]int, len(p.Roster)),
+		Roles:        make([]Role, len(p.Roster)),
+	}
+	copy(X.Roles, prev.roles)
+	for cid, rid := range prev.raids {
+		X.Distribution[cid] = Max(rid, -1)
+	}
+
+	if !X.Repair(rand.New(rand.NewSource(time.Now().UnixNano()))) {
+		p.logf("Previous split cannot be repaired, starting from random splits: %v", X.Violations())
+		return nil
+	}
+	return X
+}
+
+// Returns the name of a raid of the previous split, or of the bench
+func (prev *previousSplit) raidName(rid int) string {
+	if rid < 0 {
+		return "Bench"
+	} else if rid < len(prev.names) && prev.names[rid] != "" {
+		return prev.names[rid]
+	}
+	return fmt.Sprintf("Raid %d", rid+1)
+}
+
+// Moves returns the characters whose placement changed since the previous split, or nil if there is none
+func (p *Problem) Moves(X *Genome) []ReportMove {
+	prev := p.previous
+	if prev == nil {
+		return nil
+	}
+
+	moves := make([]ReportMove, 0)
+	for cid, rid := range X.Distribution {
+		char := p.Roster[cid]
+		move := ReportMove{Player: p.Players[char.Player], Name: char.Name, To: "Bench"}
+		if rid >= 0 {
+			move.To = p.RaidName(rid)
+		}
+
+		switch from := prev.raids[cid]; {
+		case from == rid:
+			continue
+		case from == noPrevious:
+			if rid < 0 {
+				continue // New character left on the bench
+			}
+		default:
+			move.From = prev.raidName(from)
+		}
+		moves = append(moves, move)
+	}
+
+	for i, rc := range prev.removed {
+		moves = append(moves, ReportMove{Player: rc.Player, Name: rc.Name, From: prev.raidName(prev.removedAt[i])})
+	}
+
+	return moves
+}
+
+// Counts the characters that played in a raid of the previous split and are now in another raid or benched
+func (p *Problem) moved(X *Genome) int {
+	if p.previous == nil {
+		return 0
+	}
+	moved := 0
+	for cid, rid := range X.Distribution {
+		if from := p.previous.raids[cid]; from >= 0 && from != rid {
+			moved += 1
+		}
+	}
+	return moved
+}
@@ -0,0 +1,178 @@
+package raidopt
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+func PrintRaid(w io.Writer, X *Genome) error {
+	p := X.problem
+	if !X.Viable() {
+		return fmt.Errorf("raid is not viable %v: %+v", X.Violations(), X)
+	}
+
+	var width int
+	for _, char := range p.Roster {
+		width = Max(width, utf8.RuneCountInString(char.Name)+1)
+	}
+
+	raids := make([][]int, X.RaidCount+1)
+	for i := range raids {
+		raids[i] = make([]int, 0)
+	}
+
+	for cid, rid := range X.Distribution {
+		raids[rid+1] = append(raids[rid+1], cid)
+	}
+
+	var longest int
+	for _, raid := range raids {
+		if len(raid) > longest {
+			longest = len(raid)
+		}
+
+		sort.Slice(raid, func(i, j int) bool {
+			a, b := p.Roster[raid[i]], p.Roster[raid[j]]
+			if ar, br := X.Roles[raid[i]], X.Roles[raid[j]]; ar != br {
+				return ar < br
+			} else if a.Class != b.Class {
+				return a.Class < b.Class
+			} else {
+				return a.Name < b.Name
+			}
+		})
+	}
+
+	type RaidStats struct {
+		RoleCount map[Role]int
+	}
+
+	stats := make([]RaidStats, X.RaidCount+1)
+
+	for i := 0; i <= X.RaidCount; i++ {
+		stats[i].RoleCount = map[Role]int{Tank: 0, Healer: 0, Melee: 0, Ranged: 0}
+	}
+
+	// Column headers
+	fmt.Fprintf(w, "%-*s   ", width+8, "Bench")
+	for rid := 0; rid < X.RaidCount; rid++ {
+		fmt.Fprintf(w, "%-*s   ", width+8, p.RaidName(rid))
+	}
+	fmt.Fprint(w, "\n")
+
+	for row := 0; row < longest; row++ {
+		for col := 0; col <= X.RaidCount; col++ {
+			if row >= len(raids[col]) {
+				fmt.Fprintf(w, "%s %-7s   ", Character{}.Colored(width), "")
+				continue
+			}
+
+			cid := raids[col][row]
+			char, role := p.Roster[cid], X.Roles[cid]
+			stats[col].RoleCount[role] += 1
+
+			// Roles other than the preferred one are flagged with a star
+			label := role.String()
+			if role != char.Role {
+				label += "*"
+			}
+
+			fmt.Fprintf(w, "%s %-7s   ", char.Colored(width), label)
+		}
+		fmt.Fprint(w, "\n")
+	}
+
+	fmt.Fprintf(w, "%v\n\n", stats)
+	p.Strategy.PrintStats(w, X)
+	printFitness(w, X.Fitness())
+	printMoves(w, p.Moves(X))
+	return nil
+}
+
+// Prints the fitness of every split side by side, with the value of each objective
+func printSummary(w io.Writer, title string, reports []SplitReport) {
+	if len(reports) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n%-6s %-6s %14s", title, "Split", "Raids", "Total")
+	for _, t := range reports[0].Fitness.Terms {
+		fmt.Fprintf(w, " %14s", t.Name)
+	}
+	fmt.Fprint(w, "\n")
+
+	for i, report := range reports {
+		fmt.Fprintf(w, "%-6d %-6d %14f", i+1, report.RaidCount, report.Fitness.Total)
+		for _, t := range report.Fitness.Terms {
+			fmt.Fprintf(w, " %14f", t.Value)
+		}
+		fmt.Fprint(w, "\n")
+	}
+}
+
+// Prints the contribution of each objective to the fitness
+func printFitness(w io.Writer, fb FitnessBreakdown) {
+	fmt.Fprintf(w, "\nFitness: %f\n", fb.Total())
+	for _, t := range fb {
+		fmt.Fprintf(w, "  %-14s %14f x %-12g = %f\n", t.Name, t.Value, t.Weight, t.Contribution())
+	}
+}
+
+// Prints a diff-style list of the moves since the previous split: + joined, - left, ~ moved
+func printMoves(w io.Writer, moves []ReportMove) {
+	if moves == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%d changes since the previous split\n", len(moves))
+	for _, move := range moves {
+		name := fmt.Sprintf("%s (%s)", move.Name, move.Player)
+		switch {
+		case move.From == "":
+			fmt.Fprintf(w, "+ %-30s %s\n", name, move.To)
+		case move.To == "":
+			fmt.Fprintf(w, "- %-30s %s\n", name, move.From)
+		default:
+			fmt.Fprintf(w, "~ %-30s %s -> %s\n", name, move.From, move.To)
+		}
+	}
+}
+
+// PrintOperatorStats prints the statistics of the mutation operators summed over the populations, along with the
+// range of their final selection probability across populations
+func PrintOperatorStats(w io.Writer, stats [][]OperatorStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "Mutation operators:\n")
+	fmt.Fprintf(w, "  %-10s %10s %8s %9s %8s %8s %8s %12s %13s\n", "operator", "attempts", "retries", "fallbacks", "rejected",
+		"changed", "improved", "gain/attempt", "final rate")
+	for i := range stats[0] {
+		total := OperatorStats{Name: stats[0][i].Name}
+		minRate, maxRate := 1.0, 0.0
+		for _, pop := range stats {
+			s := pop[i]
+			total.Attempts += s.Attempts
+			total.Retries += s.Retries
+			total.Fallbacks += s.Fallbacks
+			total.Rejections += s.Rejections
+			total.Successes += s.Successes
+			total.Improvements += s.Improvements
+			total.Gain += s.Gain
+			minRate, maxRate = math.Min(minRate, s.Rate), math.Max(maxRate, s.Rate)
+		}
+
+		if maxRate == 0 {
+			continue // Disabled operator
+		}
+
+		attempts := float64(Max(total.Attempts, 1))
+		fmt.Fprintf(w, "  %-10s %10d %8d %9d %8d %7.1f%% %7.1f%% %12.4f %5.1f%%-%5.1f%%\n", total.Name, total.Attempts,
+			total.Retries, total.Fallbacks, total.Rejections, 100*float64(total.Successes)/attempts,
+			100*float64(total.Improvements)/attempts, total.Gain/attempts, 100*minRate, 100*maxRate)
+	}
+}
@@ -0,0 +1,200 @@
+package raidopt
+
+import (
+	"log"
+	"math"
+	"sync"
+)
+
+// Problem owns the roster, the constraints and the strategy of an optimization. Independent problems can be
+// optimized concurrently.
+type Problem struct {
+	Roster      []Character
+	Players     []string
+	Constraints Constraints
+	Strategy    Strategy
+	Objectives  ObjectiveConfig // Weights of the fitness objectives, defaults apply to objectives not listed
+
+	MinRaids int // Minimum number of raids requested
+	MaxRaids int // Maximum number of raids requested, 0 for no limit
+
+	CheckViability bool        // Check raid viability after each mutation
+	Logger         *log.Logger // Progress and diagnostics, nil to discard them
+
+	rules            compiledRules
+	objectives       []weightedObjective
+	classBuffs       []uint64 // Buffs provided by each class
+	allBuffs         uint64
+	playerCharacters [][]int
+	roleIndex        struct {
+		Tank RoleIndex
+		Heal RoleIndex
+		Dps  RoleIndex
+	}
+
+	// Bounds narrowed by the roster and the feasibility analysis
+	minRaids, maxRaids int
+
+	// Raid counts proven feasible by the analysis, along with a viable distribution for each of them
+	raidCounts []int
+	witnesses  map[int]*Genome
+
+	// Previous split set by SetPrevious, and the viable genome built from it, if any
+	previous *previousSplit
+	seed     *Genome
+
+	operators operatorSelectors // Mutation operator selector of each population
+
+	prepareOnce sync.Once
+	prepareErr  error
+}
+
+type RoleIndex struct {
+	Chars []int // Every character able to play the role
+	Mains []int // Mains preferring the role
+	Alts  []int // Alts preferring the role
+	Flex  []int // Characters able to play the role but preferring another one
+	Fixed []int // Mains unable to play any other role
+}
+
+// NewProblem creates a problem for the given roster and strategy, using the default constraints and raid counts
+func NewProblem(roster []Character, players []string, strategy Strategy) *Problem {
+	p := &Problem{
+		Roster:         roster,
+		Players:        players,
+		Constraints:    DefaultConstraints,
+		Strategy:       strategy,
+		MinRaids:       2,
+		CheckViability: true,
+		witnesses:      make(map[int]*Genome),
+	}
+	p.indexRoster()
+	p.SetRules(Rules{})
+	return p
+}
+
+func (p *Problem) logf(format string, args ...any) {
+	if p.Logger != nil {
+		p.Logger.Printf(format, args...)
+	}
+}
+
+func (p *Problem) indexRoster() {
+	p.playerCharacters = make([][]int, len(p.Players))
+	for cid, char := range p.Roster {
+		player := char.Player
+		p.playerCharacters[player] = append(p.playerCharacters[player], cid)
+
+		indexed := make(map[*RoleIndex]bool)
+		for i, role := range char.Roles {
+			var ridx *RoleIndex
+			switch role {
+			case Tank:
+				ridx = &p.roleIndex.Tank
+			case Healer:
+				ridx = &p.roleIndex.Heal
+			case Melee, Ranged:
+				ridx = &p.roleIndex.Dps
+			}
+
+			if indexed[ridx] {
+				continue // Melee and ranged roles share the same index
+			}
+			indexed[ridx] = true
+
+			ridx.Chars = append(ridx.Chars, cid)
+			if i > 0 {
+				ridx.Flex = append(ridx.Flex, cid)
+			} else if char.Main {
+				ridx.Mains = append(ridx.Mains, cid)
+			} else {
+				ridx.Alts = append(ridx.Alts, cid)
+			}
+		}
+
+		if char.Main && len(indexed) == 1 {
+			for ridx := range indexed {
+				ridx.Fixed = append(ridx.Fixed, cid)
+			}
+		}
+	}
+}
+
+// requestedRaids returns the range of raid counts requested, resolving the absence of limit
+func (p *Problem) requestedRaids() (int, int) {
+	if p.MaxRaids <= 0 {
+		return p.MinRaids, len(p.Roster) // No explicit limit, the roster bounds will narrow it down
+	}
+	return p.MinRaids, p.MaxRaids
+}
+
+// ComputeBounds narrows the requested raid counts to the ones the roster can support
+func (p *Problem) ComputeBounds() {
+	p.minRaids, p.maxRaids = p.requestedRaids()
+	cs := p.Constraints
+
+	// Tanks-related bounds
+	if cs.MaxTanks > 0 {
+		p.minRaids = Max(p.minRaids, int(math.Ceil(float64(len(p.roleIndex.Tank.Fixed))/float64(cs.MaxTanks)))) // Using only mains
+	}
+	if cs.MinTanks > 0 {
+		p.maxRaids = Min(p.maxRaids, len(p.roleIndex.Tank.Chars)/cs.MinTanks) // Using every tanks
+	}
+
+	mainCount := float64(len(p.roleIndex.Tank.Mains) + len(p.roleIndex.Heal.Mains) + len(p.roleIndex.Dps.Mains))
+	charCount := float64(len(p.Roster))
+
+	// Roster-related bounds
+	p.minRaids = Max(p.minRaids, int(math.Ceil(mainCount/float64(cs.MaxRaidSize)))) // Packing mains in the minimum number of raids
+	p.maxRaids = Min(p.maxRaids, int(math.Ceil(charCount/float64(cs.MinRaidSize)))) // Spreading every char in the smallest possible raids
+
+	// Every raid a character is pinned to must exist, and only named raids can be formed
+	p.minRaids = Max(p.minRaids, p.rules.maxPin+1)
+	if len(p.rules.raidNames) > 0 {
+		p.maxRaids = Min(p.maxRaids, len(p.rules.raidNames))
+	}
+
+	// Healers-related bounds are checked for each raid count by the feasibility analysis
+
+	p.logf("Raid count bounds: %d-%d", p.minRaids, p.maxRaids)
+}
+
+// Prepare validates the problem, finds the feasible raid counts and prepares the strategy and the objectives. It is only performed
+// once, subsequent calls return the result of the first one.
+func (p *Problem) Prepare() error {
+	p.prepareOnce.Do(func() {
+		if err := p.Constraints.Validate(); err != nil {
+			p.prepareErr = err
+			return
+		}
+
+		p.ComputeBounds()
+		if p.prepareErr = p.CheckFeasibility(); p.prepareErr != nil {
+			return
+		}
+		if p.previous != nil {
+			p.seed = p.makeSeed()
+		}
+
+		if p.prepareErr = p.Strategy.Prepare(p); p.prepareErr != nil {
+			return
+		}
+		p.prepareErr = p.compileObjectives()
+	})
+	return p.prepareErr
+}
+
+// feasible returns whether the raid count was proven feasible by Prepare
+func (p *Problem) feasible(raidCount int) bool {
+	for _, count := range p.raidCounts {
+		if count == raidCount {
+			return true
+		}
+	}
+	return false
+}
+
+// RaidCounts returns the raid counts proven feasible by Prepare
+func (p *Problem) RaidCounts() []int {
+	return p.raidCounts
+}
@@ -0,0 +1,383 @@
+package raidopt
+
+import (
+	"math/rand"
+)
+
+// Rounds of composition fixes attempted by Repair before giving up
+const repairRounds = 4
+
+// Repair minimally edits the genome into a viable one: characters are first moved or benched to follow the rules,
+// raids are then trimmed and refilled to respect their composition, and benched mains are placed back. Returns false,
+// leaving the genome unchanged, if the raid count is not feasible or no viable distribution was found.
+func (X *Genome) Repair(rng *rand.Rand) bool {
+	p := X.problem
+	if X.Viable() {
+		return true
+	}
+	if p.raidCounts != nil && !p.feasible(X.RaidCount) {
+		return false // Proven infeasible by the analysis
+	}
+	return X.repair(rng)
+}
+
+// Repairs the genome regardless of the feasible raid counts, used by the feasibility analysis to find witnesses
+func (X *Genome) repair(rng *rand.Rand) bool {
+	p := X.problem
+	if X.Viable() {
+		return true
+	}
+
+	Y := X.Clone().(*Genome)
+	Y.repairRules()
+	stats := make([]RaidComp, Y.RaidCount)
+	for round := 0; round < repairRounds; round++ {
+		Y.raidComps(stats)
+		for rid := range stats {
+			if p.Constraints.Distance(stats[rid]) > 0 {
+				Y.trimRaid(rid)
+				p.fillRaid(Y, rid, false, rng)
+			}
+		}
+
+		// Characters are only taken from other raids if the bench cannot complete a raid
+		Y.raidComps(stats)
+		for rid := range stats {
+			if p.Constraints.Distance(stats[rid]) > 0 {
+				p.fillRaid(Y, rid, true, rng)
+			}
+		}
+
+		for cid, char := range p.Roster {
+			if char.Main && !p.rules.bench[cid] && Y.Distribution[cid] < 0 {
+				p.placeMain(Y, rng, cid, displaceDepth)
+			}
+		}
+
+		if Y.Viable() {
+			copy(X.Distribution, Y.Distribution)
+			copy(X.Roles, Y.Roles)
+			return true
+		}
+	}
+	return false
+}
+
+// raidComps computes the composition of every raid into the given slice
+func (X *Genome) raidComps(stats []RaidComp) {
+	for rid := range stats {
+		stats[rid] = RaidComp{}
+	}
+	for cid, rid := range X.Distribution {
+		if rid >= 0 {
+			stats[rid].Add(X.Roles[cid])
+		}
+	}
+}
+
+// commit replaces the distribution of the genome with the given one if it is viable or can be repaired, returning
+// whether it did. The distribution is used as is if viability checks are disabled.
+func (X *Genome) commit(dist []int, roles []Role, raidCount int, rng *rand.Rand) bool {
+	Y := &Genome{problem: X.problem, RaidCount: raidCount, Distribution: dist, Roles: roles}
+	if X.problem.CheckViability && !Y.Repair(rng) {
+		X.record(rng, eventRejection)
+		return false
+	}
+	X.RaidCount = raidCount
+	copy(X.Distribution, dist)
+	copy(X.Roles, roles)
+	return true
+}
+
+// Moves or benches characters to follow the pins, bench, availability, together and apart rules, and resolves
+// duplicate players. Conflicts are resolved in favor of pinned characters, then mains.
+func (X *Genome) repairRules() {
+	p := X.problem
+	for cid, rid := range X.Distribution {
+		char := p.Roster[cid]
+		if rid >= X.RaidCount {
+			X.Distribution[cid], rid = -1, -1
+		}
+		if pin := p.rules.pins[cid]; pin >= 0 && pin < X.RaidCount {
+			X.Distribution[cid], rid = pin, pin
+		} else if rid >= 0 && (p.rules.bench[cid] || !p.Available(char.Player, rid)) {
+			X.Distribution[cid], rid = -1, -1
+		}
+		if rid >= 0 && !char.HasRole(X.Roles[cid]) {
+			X.Roles[cid] = char.Role
+		}
+	}
+
+	// Gather the placed characters of each group in the raid of its pinned character, or of its first placed one
+	for _, group := range p.rules.groups {
+		target := -1
+		for _, cid := range group {
+			if rid := X.Distribution[cid]; rid >= 0 && (target < 0 || p.pinned(cid)) {
+				target = rid
+			}
+		}
+		for _, cid := range group {
+			if rid := X.Distribution[cid]; rid >= 0 && rid != target {
+				if p.Available(p.Roster[cid].Player, target) && !p.pinned(cid) {
+					X.Distribution[cid] = target
+				} else {
+					X.Distribution[cid] = -1
+				}
+			}
+		}
+	}
+
+	// Characters are kept in priority order, those conflicting with an already kept one are benched
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+	kept := make([]bool, len(p.Roster))
+next:
+	for _, cid := range p.rules.priority {
+		rid := X.Distribution[cid]
+		if rid < 0 {
+			continue
+		}
+		if playerRaids.Has(p.Roster[cid].Player, rid) {
+			X.Distribution[cid] = -1
+			continue
+		}
+		for _, oid := range p.rules.rivals[cid] {
+			if kept[oid] && X.Distribution[oid] == rid {
+				X.Distribution[cid] = -1
+				continue next
+			}
+		}
+		playerRaids.Add(p.Roster[cid].Player, rid)
+		kept[cid] = true
+	}
+}
+
+// Switches roles or benches characters of a raid having too many members for its constraints, picking at each step
+// the change bringing the raid the closest to the constraints. Role switches are preferred, then benching alts.
+func (X *Genome) trimRaid(rid int) {
+	p := X.problem
+	var stats RaidComp
+	for cid, orid := range X.Distribution {
+		if orid == rid {
+			stats.Add(X.Roles[cid])
+		}
+	}
+
+	for {
+		distance := p.Constraints.Distance(stats)
+		if distance == 0 {
+			return
+		}
+
+		best, bench, bestRole, bestDistance, bestCost := -1, false, Role(0), distance, 0
+		for cid, orid := range X.Distribution {
+			if orid != rid || p.pinned(cid) {
+				continue
+			}
+			char, current := p.Roster[cid], X.Roles[cid]
+
+			// Benching costs more than switching role, and benching a main more than benching an alt
+			cost := 1
+			if char.Main {
+				cost = 2
+			}
+			if d := p.Constraints.Distance(stats.Without(current)); d < bestDistance || (d == bestDistance && best >= 0 && cost < bestCost) {
+				best, bench, bestDistance, bestCost = cid, true, d, cost
+			}
+			for _, role := range char.Roles {
+				if role == current {
+					continue
+				}
+				if d := p.Constraints.Distance(stats.Replace(current, role)); d < bestDistance || (d == bestDistance && best >= 0 && 0 < bestCost) {
+					best, bench, bestRole, bestDistance, bestCost = cid, false, role, d, 0
+				}
+			}
+		}
+
+		if best < 0 {
+			return
+		}
+		if bench {
+			stats.Remove(X.Roles[best])
+			X.Distribution[best] = -1
+		} else {
+			stats = stats.Replace(X.Roles[best], bestRole)
+			X.Roles[best] = bestRole
+		}
+	}
+}
+
+// fillRaid adds characters to a raid until its composition is valid again, picking at each step the character
+// bringing the raid the closest to the constraints. Candidates are benched characters and, if steal is set,
+// characters of other raids that can leave them without breaking their composition. Benched characters are
+// preferred, then mains.
+func (p *Problem) fillRaid(X *Genome, rid int, steal bool, rng *rand.Rand) {
+	stats := make([]RaidComp, X.RaidCount)
+	playerRaids := p.MakePlayerRaids(X.RaidCount)
+	for cid, orid := range X.Distribution {
+		if orid >= 0 {
+			stats[orid].Add(X.Roles[cid])
+			playerRaids.Add(p.Roster[cid].Player, orid)
+		}
+	}
+
+	// Returns whether the character can join the raid, leaving its current one if any
+	candidate := func(cid int) bool {
+		char, orid := p.Roster[cid], X.Distribution[cid]
+		if orid == rid || playerRaids.Has(char.Player, rid) || !p.allowed(X.Distribution, cid, rid) {
+			return false
+		}
+		return orid < 0 || (steal && p.Constraints.Check(stats[orid].Without(X.Roles[cid])) == ConstraintNone)
+	}
+
+	// Returns whether joining a is preferable to joining b, for the same distance
+	preferred := func(a, b int) bool {
+		if (X.Distribution[a] < 0) != (X.Distribution[b] < 0) {
+			return X.Distribution[a] < 0
+		}
+		return p.Roster[a].Main && !p.Roster[b].Main
+	}
+
+	// Candidates are visited from a random offset, which is enough to vary the picks among equivalent characters
+	n := len(X.Distribution)
+	for stats[rid].Count < p.Constraints.MaxRaidSize {
+		distance := p.Constraints.Distance(stats[rid])
+		if distance == 0 {
+			return
+		}
+
+		start := rng.Intn(n)
+		best, bestRole, bestDistance := -1, Role(0), distance
+		for i := 0; i < n; i++ {
+			cid := (start + i) % n
+			if !candidate(cid) {
+				continue
+			}
+			for _, role := range p.Roster[cid].Roles {
+				d := p.Constraints.Distance(stats[rid].With(role))
+				if d < bestDistance || (d == bestDistance && best >= 0 && preferred(cid, best)) {
+					best, bestRole, bestDistance = cid, role, d
+				}
+			}
+		}
+
+		if best < 0 {
+			// No character brings the raid closer, try a dps keeping the distance to unlock the healer ratio
+			for i := 0; i < n; i++ {
+				cid := (start + i) % n
+				role := p.Roster[cid].Role
+				if (role == Melee || role == Ranged) && candidate(cid) && p.Constraints.Distance(stats[rid].With(role)) == distance {
+					best, bestRole = cid, role
+					break
+				}
+			}
+			if best < 0 {
+				return
+			}
+		}
+
+		player := p.Roster[best].Player
+		if orid := X.Distribution[best]; orid >= 0 {
+			stats[orid].Remove(X.Roles[best])
+			playerRaids.Remove(player, orid)
+		}
+		X.Distribution[best], X.Roles[best] = rid, bestRole
+		stats[rid].Add(bestRole)
+		playerRaids.Add(player, rid)
+	}
+}
+
+// Number of mains placeMain may displace in a chain to place a benched main
+const displaceDepth = 2
+
+// Places a benched main into a raid: in a free spot, replacing an alt playing the same role or, up to depth times,
+// displacing a main playing the same role that is then placed into another raid. Another character of the same
+// player already in the raid is benched if it is neither a main nor pinned. Returns whether it succeeded, leaving the
+// genome unchanged otherwise.
+func (p *Problem) placeMain(X *Genome, rng *rand.Rand, cid int, depth int) bool {
+	char := p.Roster[cid]
+
+	// Returns the composition of the raid without the character of the same player, along with that character (-1
+	// if none), or false if the main cannot join the raid
+	open := func(rid int) (stats RaidComp, blocker int, ok bool) {
+		blocker = -1
+		for oid, orid := range X.Distribution {
+			if orid != rid {
+				continue
+			}
+			if p.Roster[oid].Player == char.Player {
+				if p.Roster[oid].Main || p.pinned(oid) {
+					return stats, -1, false
+				}
+				blocker = oid
+				continue
+			}
+			stats.Add(X.Roles[oid])
+		}
+		return stats, blocker, p.allowed(X.Distribution, cid, rid)
+	}
+
+	// Places the main, benching the character of the same player and the replaced character, if any
+	place := func(rid int, role Role, blocker int, replaced int) {
+		if blocker >= 0 {
+			X.Distribution[blocker] = -1
+		}
+		if replaced >= 0 {
+			X.Distribution[replaced] = -1
+		}
+		X.Distribution[cid], X.Roles[cid] = rid, role
+	}
+
+	raids := rng.Perm(X.RaidCount)
+	for _, rid := range raids {
+		stats, blocker, ok := open(rid)
+		if !ok {
+			continue
+		}
+		for _, role := range char.Roles {
+			if p.Constraints.Check(stats.With(role)) == ConstraintNone {
+				place(rid, role, blocker, -1)
+				return true
+			}
+		}
+	}
+
+	for _, rid := range raids {
+		_, blocker, ok := open(rid)
+		if !ok {
+			continue
+		}
+		for oid, orid := range X.Distribution {
+			if orid == rid && oid != blocker && !p.Roster[oid].Main && !p.pinned(oid) && char.HasRole(X.Roles[oid]) {
+				place(rid, X.Roles[oid], blocker, oid)
+				return true
+			}
+		}
+	}
+
+	if depth == 0 {
+		return false
+	}
+	for _, rid := range raids {
+		_, blocker, ok := open(rid)
+		if !ok {
+			continue
+		}
+		for oid, orid := range X.Distribution {
+			if orid != rid || oid == blocker || p.pinned(oid) || !char.HasRole(X.Roles[oid]) {
+				continue
+			}
+			// Placing the displaced main elsewhere may change its role, restore it along with the raid
+			role, orole := X.Roles[cid], X.Roles[oid]
+			place(rid, X.Roles[oid], blocker, oid)
+			if p.placeMain(X, rng, oid, depth-1) {
+				return true
+			}
+			X.Distribution[cid], X.Distribution[oid] = -1, rid
+			X.Roles[cid], X.Roles[oid] = role, orole
+			if blocker >= 0 {
+				X.Distribution[blocker] = rid
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,420 @@
+package raidopt
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type Character struct {
+	Player     int
+	Name       string
+	Class      Class
+	Role       Role             // Preferred role
+	Roles      []Role           // Every role the character can play, in order of preference
+	Spec       Specialization   // Preferred spec, zero if not provided
+	Specs      []Specialization // Every spec provided, in order of preference
+	Main       bool
+	TokenSlots TokenSlotSet
+}
+
+func (char Character) String() string {
+	return char.Name
+}
+
+// Colored returns the name of the character padded to the given width, colored according to its class
+func (char Character) Colored(width int) string {
+	if char.Class < Warrior {
+		return fmt.Sprintf("%-"+strconv.Itoa(width)+"s", "")
+	}
+
+	r, g, b := ClassColor(char.Class)
+	var format string
+	if char.Main {
+		format = fmt.Sprintf("\x1b[48;2;%d;%d;%dm\x1b[38;5;0m", r, g, b)
+	} else {
+		format = fmt.Sprintf("\x1b[38;2;%d;%d;%dm", r, g, b)
+	}
+
+	return fmt.Sprintf("%s%-"+strconv.Itoa(width)+"s\x1b[0m", format, char.Name)
+}
+
+// HasRole returns whether the character can play the given role
+func (char Character) HasRole(role Role) bool {
+	return hasRole(char.Roles, role)
+}
+
+// FirstRole returns the preferred role of the character among the ones matching the mask, or zero if none does
+func (char Character) FirstRole(mask Role) Role {
+	for _, role := range char.Roles {
+		if role&mask != 0 {
+			return role
+		}
+	}
+	return 0
+}
+
+// Flexible returns whether the character can play more than one role
+func (char Character) Flexible() bool {
+	return len(char.Roles) > 1
+}
+
+func hasRole(roles []Role, role Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Appends the role to the list unless it is already present
+func appendRole(roles []Role, role Role) []Role {
+	if hasRole(roles, role) {
+		return roles
+	}
+	return append(roles, role)
+}
+
+// Splits a slash-separated list, ignoring empty items
+func splitList(str string) (items []string) {
+	for _, item := range strings.Split(str, "/") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return
+}
+
+// Roster columns
+const (
+	ColPlayer     = "player"
+	ColRole       = "role"
+	ColName       = "name"
+	ColClass      = "class"
+	ColMain       = "main"
+	ColTokenSlots = "token_slots"
+	ColSpec       = "spec"
+)
+
+// Columns of a roster file without header, in order
+var defaultColumns = []string{ColPlayer, ColRole, ColName, ColClass, ColMain, ColTokenSlots}
+
+// Columns that must be present in every roster, in addition to either the role or the spec
+var requiredColumns = []string{ColPlayer, ColName, ColClass}
+
+var columnAliases = map[string]string{
+	"player":      ColPlayer,
+	"role":        ColRole,
+	"roles":       ColRole,
+	"name":        ColName,
+	"character":   ColName,
+	"char":        ColName,
+	"class":       ColClass,
+	"main":        ColMain,
+	"is_main":     ColMain,
+	"token_slots": ColTokenSlots,
+	"tokens":      ColTokenSlots,
+	"slots":       ColTokenSlots,
+	"spec":        ColSpec,
+	"specs":       ColSpec,
+}
+
+// Returns the canonical column name for a header cell, or an empty string if the column is unknown
+func ParseColumn(str string) string {
+	str = strings.ToLower(strings.TrimSpace(str))
+	str = strings.NewReplacer(" ", "_", "-", "_").Replace(str)
+	return columnAliases[str]
+}
+
+// RosterError is an error located in the roster input
+type RosterError struct {
+	Row      int
+	Location string // Used instead of the row for structured formats
+	Column   string
+	Err      error
+}
+
+func (e *RosterError) Error() string {
+	location := e.Location
+	if location == "" {
+		location = fmt.Sprintf("row %d", e.Row)
+	}
+	if e.Column == "" {
+		return fmt.Sprintf("%s: %s", location, e.Err)
+	}
+	return fmt.Sprintf("%s, column %s: %s", location, e.Column, e.Err)
+}
+
+func (e *RosterError) Unwrap() error {
+	return e.Err
+}
+
+// RosterRecord gives access to the fields of a roster entry by column name
+type RosterRecord struct {
+	Row      int
+	Location string
+	columns  map[string]int
+	values   []string
+}
+
+// Has returns whether the record has a non-empty value for the column
+func (r RosterRecord) Has(column string) bool {
+	return r.Get(column) != ""
+}
+
+// Get returns the value of the column, or an empty string if the record does not have it
+func (r RosterRecord) Get(column string) string {
+	if idx, found := r.columns[column]; found && idx < len(r.values) {
+		return strings.TrimSpace(r.values[idx])
+	}
+	return ""
+}
+
+// Errorf returns a RosterError located at the given column of the record
+func (r RosterRecord) Errorf(column string, format string, args ...any) error {
+	return &RosterError{Row: r.Row, Location: r.Location, Column: column, Err: fmt.Errorf(format, args...)}
+}
+
+// ParseBool parses common boolean spellings found in spreadsheets
+func ParseBool(str string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(str)) {
+	case "true", "t", "yes", "y", "1", "x", "main":
+		return true, nil
+	case "false", "f", "no", "n", "0", "", "alt":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean: %q", str)
+}
+
+// RosterBuilder accumulates records into characters and players
+type RosterBuilder struct {
+	strategy    Strategy
+	roster      []Character
+	players     []string
+	playerIndex map[string]int
+}
+
+// NewRosterBuilder creates a builder letting the strategy load its own fields from each record
+func NewRosterBuilder(strategy Strategy) *RosterBuilder {
+	return &RosterBuilder{
+		strategy:    strategy,
+		roster:      make([]Character, 0),
+		players:     make([]string, 0),
+		playerIndex: make(map[string]int),
+	}
+}
+
+// Add parses a record and appends the resulting character to the roster
+func (rb *RosterBuilder) Add(record RosterRecord) error {
+	for _, column := range requiredColumns {
+		if _, found := record.columns[column]; !found {
+			return record.Errorf(column, "missing column")
+		}
+	}
+	if !record.Has(ColRole) && !record.Has(ColSpec) {
+		return record.Errorf(ColRole, "missing role or spec")
+	}
+
+	player := record.Get(ColPlayer)
+	if player == "" {
+		return record.Errorf(ColPlayer, "empty player")
+	}
+
+	name := record.Get(ColName)
+	if name == "" {
+		return record.Errorf(ColName, "empty name")
+	}
+
+	class, err := ParseClass(record.Get(ColClass))
+	if err != nil {
+		return record.Errorf(ColClass, "%s", err)
+	}
+
+	// Specs and roles may list several values separated by slashes, in order of preference
+	var specs []Specialization
+	for _, str := range splitList(record.Get(ColSpec)) {
+		spec, err := ParseSpec(class, str)
+		if err != nil {
+			return record.Errorf(ColSpec, "%s", err)
+		}
+		specs = append(specs, spec)
+	}
+
+	var roles []Role
+	for _, str := range splitList(record.Get(ColRole)) {
+		role, err := ParseRole(str)
+		if err != nil {
+			return record.Errorf(ColRole, "%s", err)
+		}
+		roles = appendRole(roles, role)
+	}
+
+	if len(roles) == 0 {
+		// Derive the roles from the specs
+		for _, spec := range specs {
+			roles = appendRole(roles, GetRole(spec))
+		}
+	} else if len(specs) > 0 {
+		// Every spec must match one of the roles, and every role must be backed by a spec
+		for _, spec := range specs {
+			if !hasRole(roles, GetRole(spec)) {
+				return record.Errorf(ColSpec, "%s %s is a %s spec but the role is %s", spec, class, GetRole(spec), record.Get(ColRole))
+			}
+		}
+		for _, role := range roles {
+			backed := false
+			for _, spec := range specs {
+				backed = backed || GetRole(spec) == role
+			}
+			if !backed {
+				return record.Errorf(ColRole, "no %s spec listed for role %s", class, role)
+			}
+		}
+	}
+
+	main, err := ParseBool(record.Get(ColMain))
+	if err != nil {
+		return record.Errorf(ColMain, "%s", err)
+	}
+
+	if _, found := rb.playerIndex[player]; !found {
+		rb.playerIndex[player] = len(rb.players)
+		rb.players = append(rb.players, player)
+	}
+
+	char := Character{
+		Player: rb.playerIndex[player],
+		Name:   name,
+		Class:  class,
+		Role:   roles[0],
+		Roles:  roles,
+		Main:   main,
+	}
+
+	if len(specs) > 0 {
+		char.Spec = specs[0]
+		char.Specs = specs
+	}
+
+	if err := rb.strategy.LoadChar(&char, record); err != nil {
+		return err
+	}
+
+	rb.roster = append(rb.roster, char)
+	return nil
+}
+
+func (rb *RosterBuilder) Build() ([]Character, []string) {
+	return rb.roster, rb.players
+}
+
+// LoadRoster reads the roster file at the given path. The format is either csv, json or yaml, or guessed from the
+// file extension if empty.
+func LoadRoster(path string, format string, strategy Strategy) ([]Character, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	format = strings.ToLower(format)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json":
+			format = "json"
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			format = "csv"
+		}
+	}
+
+	var roster []Character
+	var players []string
+	switch format {
+	case "csv":
+		roster, players, err = ReadCSVRoster(f, strategy)
+	case "json":
+		roster, players, err = ReadJSONRoster(f, strategy)
+	case "yaml":
+		roster, players, err = ReadYAMLRoster(f, strategy)
+	default:
+		return nil, nil, fmt.Errorf("unknown roster format: %s", format)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return roster, players, nil
+}
+
+// ReadCSVRoster reads a CSV roster. If the first row is a header, columns are mapped by name, otherwise they are
+// expected in the order of defaultColumns.
+func ReadCSVRoster(r io.Reader, strategy Strategy) ([]Character, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	builder := NewRosterBuilder(strategy)
+	var columns map[string]int
+
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+		row, _ := reader.FieldPos(0)
+
+		if columns == nil {
+			if header := parseHeader(values); header != nil {
+				columns = header
+				continue
+			}
+
+			columns = make(map[string]int)
+			for i, column := range defaultColumns {
+				columns[column] = i
+			}
+		}
+
+		if err := builder.Add(RosterRecord{Row: row, columns: columns, values: values}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	roster, players := builder.Build()
+	return roster, players, nil
+}
+
+// Returns the column mapping if the row is a header, nil otherwise. A row is considered a header if it names at
+// least two of the required columns. Unknown columns are ignored.
+func parseHeader(values []string) map[string]int {
+	columns := make(map[string]int)
+	for i, value := range values {
+		if column := ParseColumn(value); column != "" {
+			columns[column] = i
+		}
+	}
+
+	known := 0
+	for _, column := range requiredColumns {
+		if _, found := columns[column]; found {
+			known += 1
+		}
+	}
+
+	if known < 2 {
+		return nil
+	}
+	return columns
+}
@@ -0,0 +1,95 @@
+package raidopt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Structured rosters (JSON, YAML) list players along with their characters:
+//
+//	{"players": [{"name": "Alice", "characters": [{"name": "Alicia", "class": "mage", "role": "ranged", "main": true}]}]}
+//
+// The document may also be a bare list of players. Character fields use the same names as CSV columns.
+type rosterDocument struct {
+	Players []rosterPlayer `json:"players" yaml:"players"`
+}
+
+type rosterPlayer struct {
+	Name       string           `json:"name" yaml:"name"`
+	Characters []map[string]any `json:"characters" yaml:"characters"`
+}
+
+func ReadJSONRoster(r io.Reader, strategy Strategy) ([]Character, []string, error) {
+	return readStructuredRoster(r, strategy, json.Unmarshal)
+}
+
+func ReadYAMLRoster(r io.Reader, strategy Strategy) ([]Character, []string, error) {
+	return readStructuredRoster(r, strategy, yaml.Unmarshal)
+}
+
+func readStructuredRoster(r io.Reader, strategy Strategy, unmarshal func([]byte, any) error) ([]Character, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var doc rosterDocument
+	if err := unmarshal(data, &doc); err != nil {
+		// The document may be a bare list of players
+		if errList := unmarshal(data, &doc.Players); errList != nil {
+			return nil, nil, err
+		}
+	}
+
+	builder := NewRosterBuilder(strategy)
+	row := 0
+	for p, player := range doc.Players {
+		for c, fields := range player.Characters {
+			row += 1
+			record := RosterRecord{
+				Row:      row,
+				Location: fmt.Sprintf("players[%d].characters[%d]", p, c),
+				columns:  map[string]int{ColPlayer: 0},
+				values:   []string{player.Name},
+			}
+
+			for key, value := range fields {
+				column := ParseColumn(key)
+				if column == "" || column == ColPlayer {
+					continue // Unknown fields are ignored, the player is given by the enclosing object
+				}
+				record.columns[column] = len(record.values)
+				record.values = append(record.values, structuredValue(value))
+			}
+
+			if err := builder.Add(record); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	roster, players := builder.Build()
+	return roster, players, nil
+}
+
+// Converts a decoded value to its CSV representation, lists are joined with slashes
+func structuredValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = structuredValue(item)
+		}
+		return strings.Join(parts, "/")
+	default:
+		return fmt.Sprint(v)
+	}
+}
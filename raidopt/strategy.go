@@ -0,0 +1,44 @@
+package raidopt
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type Strategy interface {
+	LoadChar(char *Character, record RosterRecord) error
+	Prepare(p *Problem) error
+	Objectives() []Objective // Objectives specific to the strategy, on top of the common ones
+	PrintStats(w io.Writer, X *Genome)
+	Stats(X *Genome) []StrategyStat
+}
+
+// StrategyStat is a receiver to trader ratio of a raid tracked by a strategy, along with its target
+type StrategyStat struct {
+	Raid      int     `json:"raid"`
+	Group     string  `json:"group"`
+	Receivers int     `json:"receivers"`
+	Traders   int     `json:"traders"`
+	Ratio     float64 `json:"ratio"`
+	Target    float64 `json:"target"`
+}
+
+// ParseStrategy returns the strategy with the given name, configured from its arguments
+func ParseStrategy(s string, args []string) (Strategy, error) {
+	switch strings.ToLower(s) {
+	case "armor":
+		return &ArmorStrategy{}, nil
+	case "token":
+		if len(args) < 1 {
+			return nil, fmt.Errorf("missing target slots for the token strategy")
+		}
+		slots, err := ParseTokenSlots(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid target slots: %w", err)
+		}
+		return &TokenStrategy{targetSlots: slots}, nil
+	}
+
+	return nil, fmt.Errorf("unknown strategy: %s", s)
+}
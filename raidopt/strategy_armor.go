@@ -0,0 +1,149 @@
+package raidopt
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+type ArmorStrategy struct {
+	targets [4]float64
+}
+
+func (ArmorStrategy) String() string {
+	return "Armor"
+}
+
+type ArmorRaidStats struct {
+	ArmorReceiver [4]int
+	ArmorTrader   [4]int
+}
+
+func (ArmorStrategy) LoadChar(char *Character, record RosterRecord) error {
+	return nil
+}
+
+func (as *ArmorStrategy) Prepare(p *Problem) error {
+	p.logf("Computing armor targets...")
+
+	var armorReceiver, armorTrader [4]int
+
+	for _, char := range p.Roster {
+		if char.Main {
+			armorReceiver[ArmorForClass(char.Class)] += 1
+		} else {
+			armorTrader[ArmorForClass(char.Class)] += 1
+		}
+	}
+
+	for i := Cloth; i <= Plate; i++ {
+		if armorReceiver[i] > 0 {
+			as.targets[i] = float64(armorTrader[i]) / float64(armorReceiver[i])
+		}
+	}
+
+	p.logf("Theoretical optimums: %+v", as.targets)
+	return nil
+}
+
+func (as ArmorStrategy) ComputeStats(X *Genome) []ArmorRaidStats {
+	raids := make([]ArmorRaidStats, X.RaidCount)
+
+	for cid, rid := range X.Distribution {
+		char := X.problem.Roster[cid]
+		if rid < 0 {
+			continue // Benched
+		}
+
+		if char.Main {
+			raids[rid].ArmorReceiver[ArmorForClass(char.Class)] += 1
+		} else {
+			raids[rid].ArmorTrader[ArmorForClass(char.Class)] += 1
+		}
+	}
+
+	return raids
+}
+
+func (as *ArmorStrategy) Objectives() []Objective {
+	return []Objective{
+		{Name: "armor", Weight: 1000, Evaluate: func(X *Genome) float64 { return as.Balance(X) }},
+	}
+}
+
+// Balance returns the sum of the distances between the armor ratios of each raid and their targets
+func (as ArmorStrategy) Balance(X *Genome) float64 {
+	raids := as.ComputeStats(X)
+
+	var delta float64
+	for rid := 0; rid < X.RaidCount; rid++ {
+		for i := Cloth; i <= Plate; i++ {
+			if raids[rid].ArmorReceiver[i] > 0 {
+				ratio := float64(raids[rid].ArmorTrader[i]) / float64(raids[rid].ArmorReceiver[i])
+				delta += math.Abs(as.targets[i] - ratio)
+			}
+		}
+	}
+
+	return delta
+}
+
+func (as ArmorStrategy) Stats(X *Genome) (stats []StrategyStat) {
+	raids := as.ComputeStats(X)
+
+	for rid := 0; rid < X.RaidCount; rid++ {
+		for i := Cloth; i <= Plate; i++ {
+			stat := StrategyStat{
+				Raid:      rid + 1,
+				Group:     i.String(),
+				Receivers: raids[rid].ArmorReceiver[i],
+				Traders:   raids[rid].ArmorTrader[i],
+				Target:    as.targets[i],
+			}
+			if stat.Receivers > 0 {
+				stat.Ratio = float64(stat.Traders) / float64(stat.Receivers)
+			}
+			stats = append(stats, stat)
+		}
+	}
+
+	return
+}
+
+func (as ArmorStrategy) PrintStats(w io.Writer, X *Genome) {
+	stats := as.ComputeStats(X)
+
+	armorRatio := [4][]float64{{}, {}, {}, {}}
+	for rid := 0; rid < X.RaidCount; rid++ {
+		fmt.Fprintf(w, "[Raid %2d] ", rid+1)
+		for i := Cloth; i <= Plate; i++ {
+			fmt.Fprintf(w, "%s %2d:%-2d", i, stats[rid].ArmorReceiver[i], stats[rid].ArmorTrader[i])
+			var ratio float64
+			if stats[rid].ArmorReceiver[i] > 0 {
+				ratio = float64(stats[rid].ArmorTrader[i]) / float64(stats[rid].ArmorReceiver[i])
+				armorRatio[i] = append(armorRatio[i], ratio)
+			}
+			fmt.Fprintf(w, " (%f)", ratio)
+			fmt.Fprintf(w, "\t")
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	fmt.Fprintf(w, "[Average] ")
+	for i := Cloth; i <= Plate; i++ {
+		var sum float64
+		var count float64
+		for _, ratio := range armorRatio[i] {
+			sum += ratio
+			count += 1
+		}
+		fmt.Fprintf(w, "%s        %f \t", i, sum/count)
+	}
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "[Optimal] ")
+	for i := Cloth; i <= Plate; i++ {
+		fmt.Fprintf(w, "%s        %f \t", i, as.targets[i])
+	}
+	fmt.Fprintf(w, "\n")
+}
@@ -1,9 +1,8 @@
-package main
+package raidopt
 
 import (
-	"flag"
 	"fmt"
-	"log"
+	"io"
 	"math"
 )
 
@@ -28,8 +27,12 @@ type TokenRaidStats struct {
 	ArmorTrader   [4][5]int
 }
 
-func (TokenStrategy) LoadChar(char *Character, record []string) {
-	char.TokenSlots = ParseTokenSlots(record[5])
+func (TokenStrategy) LoadChar(char *Character, record RosterRecord) (err error) {
+	char.TokenSlots, err = ParseTokenSlots(record.Get(ColTokenSlots))
+	if err != nil {
+		return record.Errorf(ColTokenSlots, "%s", err)
+	}
+	return nil
 }
 
 func (TokenStrategy) TokenRole(c Character, s TokenSlot) int {
@@ -42,13 +45,12 @@ func (TokenStrategy) TokenRole(c Character, s TokenSlot) int {
 	}
 }
 
-func (ts *TokenStrategy) Prepare() {
-	ts.targetSlots = ParseTokenSlots(flag.Arg(1))
-	log.Printf("Computing token targets (%s)...", ts.targetSlots)
+func (ts *TokenStrategy) Prepare(p *Problem) error {
+	p.logf("Computing token targets (%s)...", ts.targetSlots)
 
 	var tokenReceiver, tokenTrader [4][5]int
 
-	for _, char := range roster {
+	for _, char := range p.Roster {
 		for slot := SlotHead; slot <= SlotLegs; slot++ {
 			if !ts.targetSlots.Has(slot) {
 				continue
@@ -71,15 +73,15 @@ func (ts *TokenStrategy) Prepare() {
 		}
 	}
 
-	log.Printf("Theoretical optimums: %+v", ts.targets)
-	ts.as.Prepare()
+	p.logf("Theoretical optimums: %+v", ts.targets)
+	return ts.as.Prepare(p)
 }
 
-func (ts TokenStrategy) ComputeStats(X *Genome) [RMAX]TokenRaidStats {
-	var raids [RMAX]TokenRaidStats
+func (ts TokenStrategy) ComputeStats(X *Genome) []TokenRaidStats {
+	raids := make([]TokenRaidStats, X.RaidCount)
 
 	for cid, rid := range X.Distribution {
-		char := roster[cid]
+		char := X.problem.Roster[cid]
 		if rid < 0 {
 			continue // Benched
 		}
@@ -100,7 +102,14 @@ func (ts TokenStrategy) ComputeStats(X *Genome) [RMAX]TokenRaidStats {
 	return raids
 }
 
-func (ts TokenStrategy) Fitness(X *Genome) float64 {
+func (ts *TokenStrategy) Objectives() []Objective {
+	return append([]Objective{
+		{Name: "token", Weight: 100000000, Evaluate: func(X *Genome) float64 { return ts.Balance(X) }},
+	}, ts.as.Objectives()...)
+}
+
+// Balance returns the sum of the distances between the token ratios of each raid and their targets
+func (ts TokenStrategy) Balance(X *Genome) float64 {
 	raids := ts.ComputeStats(X)
 
 	var delta float64
@@ -118,10 +127,37 @@ func (ts TokenStrategy) Fitness(X *Genome) float64 {
 		}
 	}
 
-	return delta*100000 + ts.as.Fitness(X)
+	return delta
+}
+
+func (ts TokenStrategy) Stats(X *Genome) (stats []StrategyStat) {
+	raids := ts.ComputeStats(X)
+
+	for s := SlotHead; s <= SlotLegs; s++ {
+		if !ts.targetSlots.Has(s) {
+			continue
+		}
+		for rid := 0; rid < X.RaidCount; rid++ {
+			for t := Mystic; t <= Dreadful; t++ {
+				stat := StrategyStat{
+					Raid:      rid + 1,
+					Group:     fmt.Sprintf("%s %s", t, s),
+					Receivers: raids[rid].ArmorReceiver[t][s],
+					Traders:   raids[rid].ArmorTrader[t][s],
+					Target:    ts.targets[t][s],
+				}
+				if stat.Receivers > 0 {
+					stat.Ratio = float64(stat.Traders) / float64(stat.Receivers)
+				}
+				stats = append(stats, stat)
+			}
+		}
+	}
+
+	return append(stats, ts.as.Stats(X)...)
 }
 
-func (ts TokenStrategy) PrintStats(X *Genome) {
+func (ts TokenStrategy) PrintStats(w io.Writer, X *Genome) {
 	stats := ts.ComputeStats(X)
 
 	armorRatio := [4][5][]float64{{}, {}, {}, {}}
@@ -130,21 +166,21 @@ func (ts TokenStrategy) PrintStats(X *Genome) {
 			continue
 		}
 		for rid := 0; rid < X.RaidCount; rid++ {
-			fmt.Printf("[Raid %2d] ", rid+1)
+			fmt.Fprintf(w, "[Raid %2d] ", rid+1)
 			for t := Mystic; t <= Dreadful; t++ {
-				fmt.Printf("%s %s %2d:%-2d", t, s, stats[rid].ArmorReceiver[t][s], stats[rid].ArmorTrader[t][s])
+				fmt.Fprintf(w, "%s %s %2d:%-2d", t, s, stats[rid].ArmorReceiver[t][s], stats[rid].ArmorTrader[t][s])
 				var ratio float64
 				if stats[rid].ArmorReceiver[t][s] > 0 {
 					ratio = float64(stats[rid].ArmorTrader[t][s]) / float64(stats[rid].ArmorReceiver[t][s])
 					armorRatio[t][s] = append(armorRatio[t][s], ratio)
 				}
-				fmt.Printf(" (%f)", ratio)
-				fmt.Printf("\t")
+				fmt.Fprintf(w, " (%f)", ratio)
+				fmt.Fprintf(w, "\t")
 			}
-			fmt.Printf("\n")
+			fmt.Fprintf(w, "\n")
 		}
 
-		fmt.Printf("[Average] ")
+		fmt.Fprintf(w, "[Average] ")
 		for t := Mystic; t <= Dreadful; t++ {
 			var sum float64
 			var count float64
@@ -152,15 +188,15 @@ func (ts TokenStrategy) PrintStats(X *Genome) {
 				sum += ratio
 				count += 1
 			}
-			fmt.Printf("%s %s        %f \t", t, s, sum/count)
+			fmt.Fprintf(w, "%s %s        %f \t", t, s, sum/count)
 		}
-		fmt.Printf("\n")
+		fmt.Fprintf(w, "\n")
 
-		fmt.Printf("[Optimal] ")
+		fmt.Fprintf(w, "[Optimal] ")
 		for t := Mystic; t <= Dreadful; t++ {
-			fmt.Printf("%s %s        %f \t", t, s, ts.targets[t][s])
+			fmt.Fprintf(w, "%s %s        %f \t", t, s, ts.targets[t][s])
 		}
-		fmt.Printf("\n\n")
+		fmt.Fprintf(w, "\n\n")
 	}
-	ts.as.PrintStats(X)
+	ts.as.PrintStats(w, X)
 }
@@ -0,0 +1,108 @@
+package raidopt
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// PopulationStats describes a population after a generation
+type PopulationStats struct {
+	Generation uint
+	Population int
+	Best       float64
+	Mean       float64
+	Diversity  float64         // Mean distance to the best genome, see Genome.Distance
+	Operators  []OperatorStats // Cumulated statistics of the mutation operators
+}
+
+// Computes the statistics of a population from its genomes and their fitness
+func populationStats(gen uint, pop int, genomes []*Genome, fitness []float64) PopulationStats {
+	ps := PopulationStats{Generation: gen, Population: pop}
+	if len(genomes) == 0 {
+		return ps
+	}
+
+	best := 0
+	for i, f := range fitness {
+		ps.Mean += f
+		if f < fitness[best] {
+			best = i
+		}
+	}
+	ps.Best, ps.Mean = fitness[best], ps.Mean/float64(len(fitness))
+
+	for _, X := range genomes {
+		ps.Diversity += float64(X.Distance(genomes[best]))
+	}
+	ps.Diversity /= float64(len(genomes))
+	return ps
+}
+
+// Attaches the statistics of the mutation operators of every population
+func (p *Problem) attachOperatorStats(stats []PopulationStats) {
+	ops := p.OperatorStats()
+	for i := range stats {
+		if i < len(ops) {
+			stats[i].Operators = ops[i]
+		}
+	}
+}
+
+// TraceWriter writes the statistics of every population after each generation as CSV, one row per population. The
+// operator counters are cumulated since the start of the optimization.
+type TraceWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func NewTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{w: csv.NewWriter(w)}
+}
+
+func (tw *TraceWriter) Write(stats []PopulationStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	if !tw.header {
+		header := []string{"generation", "population", "best", "mean", "diversity"}
+		for _, op := range stats[0].Operators {
+			for _, column := range []string{"attempts", "retries", "fallbacks", "rejections", "improvements", "rate"} {
+				header = append(header, op.Name+"_"+column)
+			}
+		}
+		if err := tw.w.Write(header); err != nil {
+			return err
+		}
+		tw.header = true
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	for _, ps := range stats {
+		row := []string{
+			strconv.FormatUint(uint64(ps.Generation), 10),
+			strconv.Itoa(ps.Population + 1),
+			format(ps.Best),
+			format(ps.Mean),
+			format(ps.Diversity),
+		}
+		for _, op := range ps.Operators {
+			row = append(row,
+				strconv.Itoa(op.Attempts),
+				strconv.Itoa(op.Retries),
+				strconv.Itoa(op.Fallbacks),
+				strconv.Itoa(op.Rejections),
+				strconv.Itoa(op.Improvements),
+				format(op.Rate),
+			)
+		}
+		if err := tw.w.Write(row); err != nil {
+			return err
+		}
+	}
+	tw.w.Flush()
+	return tw.w.Error()
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bastienclement/raid-optimizer/raidopt"
+)
+
+const maxRequestSize = 10 << 20
+
+// JobRequest is the body accepted by POST /jobs. Omitted fields take the same defaults as the command line flags.
+type JobRequest struct {
+	Roster       json.RawMessage         `json:"roster"`     // Structured roster, as accepted by the JSON roster format
+	RosterCSV    string                  `json:"roster_csv"` // CSV roster, used if no structured roster is given
+	Strategy     string                  `json:"strategy"`
+	StrategyArgs []string                `json:"strategy_args"`
+	MinRaids     int                     `json:"min"`
+	MaxRaids     int                     `json:"max"`
+	NoCheck      bool                    `json:"no_check"`
+	Constraints  raidopt.Constraints     `json:"constraints"`
+	Rules        raidopt.Rules           `json:"rules"`
+	Previous     *raidopt.SplitReport    `json:"previous"` // Previous split, as returned in the result of a job
+	Objectives   raidopt.ObjectiveConfig `json:"objectives"`
+	Optimizer    raidopt.OptimizerConfig `json:"optimizer"`
+}
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobDone      JobStatus = "done"
+	JobCancelled JobStatus = "cancelled"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is an optimization submitted to the server
+type Job struct {
+	ID          string                `json:"id"`
+	Status      JobStatus             `json:"status"`
+	Percent     uint                  `json:"percent"`
+	Progress    raidopt.Progress      `json:"progress"`
+	Error       string                `json:"error,omitempty"`
+	Result      []raidopt.SplitReport `json:"result,omitempty"`
+	ByRaidCount []raidopt.SplitReport `json:"by_raid_count,omitempty"` // Best split of each raid count
+
+	optimizer *raidopt.Optimizer
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+// JobServer runs submitted jobs in the background, a fixed number at a time
+type JobServer struct {
+	mutex sync.Mutex
+	jobs  map[string]*Job
+	order []string
+	queue chan *Job
+	next  int
+}
+
+func NewJobServer(workers int) *JobServer {
+	s := &JobServer{
+		jobs:  make(map[string]*Job),
+		queue: make(chan *Job, 1024),
+	}
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+	return s
+}
+
+// Builds a job from the request, validating everything that does not require running the optimizer
+func (s *JobServer) makeJob(req JobRequest) (*Job, error) {
+	strategy, err := raidopt.ParseStrategy(req.Strategy, req.StrategyArgs)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Constraints.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := raidopt.ParseModel(req.Optimizer.Model); err != nil {
+		return nil, err
+	}
+	if err := req.Objectives.Validate(strategy); err != nil {
+		return nil, fmt.Errorf("objectives: %w", err)
+	}
+
+	var roster []raidopt.Character
+	var players []string
+	if len(req.Roster) > 0 {
+		roster, players, err = raidopt.ReadJSONRoster(bytes.NewReader(req.Roster), strategy)
+	} else if req.RosterCSV != "" {
+		roster, players, err = raidopt.ReadCSVRoster(strings.NewReader(req.RosterCSV), strategy)
+	} else {
+		err = errors.New("missing roster")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("roster: %w", err)
+	}
+
+	s.mutex.Lock()
+	s.next += 1
+	id := strconv.Itoa(s.next)
+	s.mutex.Unlock()
+
+	problem := raidopt.NewProblem(roster, players, strategy)
+	problem.Constraints = req.Constraints
+	problem.MinRaids, problem.MaxRaids = req.MinRaids, req.MaxRaids
+	problem.CheckViability = !req.NoCheck
+	if err := problem.SetRules(req.Rules); err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+	if req.Previous != nil {
+		if err := problem.SetPrevious(*req.Previous); err != nil {
+			return nil, fmt.Errorf("previous: %w", err)
+		}
+	}
+	problem.Objectives = req.Objectives
+	problem.Logger = log.New(os.Stderr, fmt.Sprintf("[job %s] ", id), 0)
+
+	job := &Job{ID: id, Status: JobQueued, optimizer: raidopt.NewOptimizer(problem, req.Optimizer)}
+	job.ctx, job.cancel = context.WithCancel(context.Background())
+	job.optimizer.OnProgress = func(progress raidopt.Progress) {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		job.Progress = progress
+		job.Percent = progress.Generation * 100 / progress.Generations
+	}
+
+	return job, nil
+}
+
+func (s *JobServer) work() {
+	for job := range s.queue {
+		s.mutex.Lock()
+		if job.Status != JobQueued {
+			s.mutex.Unlock()
+			continue // Cancelled while queued
+		}
+		job.Status = JobRunning
+		s.mutex.Unlock()
+
+		best, err := job.optimizer.Run(job.ctx)
+
+		var result, byRaidCount []raidopt.SplitReport
+		for _, X := range best {
+			result = append(result, raidopt.MakeReport(X))
+		}
+		for _, X := range job.optimizer.BestByRaidCount() {
+			byRaidCount = append(byRaidCount, raidopt.MakeReport(X))
+		}
+
+		s.mutex.Lock()
+		switch {
+		case err != nil:
+			job.Status = JobFailed
+			job.Error = err.Error()
+		case job.ctx.Err() != nil:
+			job.Status = JobCancelled
+		default:
+			job.Status = JobDone
+		}
+		job.Result = result
+		job.ByRaidCount = byRaidCount
+		s.mutex.Unlock()
+		job.cancel()
+	}
+}
+
+// Returns a snapshot of the job that can be encoded without holding the lock
+func (s *JobServer) snapshot(id string) (Job, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	job, found := s.jobs[id]
+	if !found {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+func (s *JobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	switch {
+	case path == "jobs" && r.Method == http.MethodPost:
+		s.submit(w, r)
+	case path == "jobs" && r.Method == http.MethodGet:
+		s.list(w)
+	case strings.HasPrefix(path, "jobs/"):
+		id := strings.TrimPrefix(path, "jobs/")
+		switch r.Method {
+		case http.MethodGet:
+			s.get(w, id)
+		case http.MethodDelete:
+			s.cancel(w, id)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		}
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// POST /jobs submits a new job
+func (s *JobServer) submit(w http.ResponseWriter, r *http.Request) {
+	req := JobRequest{
+		Strategy:    "armor",
+		MinRaids:    2,
+		Constraints: raidopt.DefaultConstraints,
+		Optimizer:   raidopt.DefaultOptimizerConfig,
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := s.makeJob(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mutex.Lock()
+	s.jobs[job.ID] = job
+	s.order = append(s.order, job.ID)
+	snapshot := *job
+	s.mutex.Unlock()
+
+	select {
+	case s.queue <- job:
+	default:
+		s.mutex.Lock()
+		job.Status = JobFailed
+		job.Error = "queue is full"
+		s.mutex.Unlock()
+		writeError(w, http.StatusServiceUnavailable, errors.New("queue is full"))
+		return
+	}
+
+	log.Printf("[job %s] queued", job.ID)
+	writeJSON(w, http.StatusAccepted, snapshot)
+}
+
+// GET /jobs lists every job without their results
+func (s *JobServer) list(w http.ResponseWriter) {
+	s.mutex.Lock()
+	jobs := make([]Job, len(s.order))
+	for i, id := range s.order {
+		jobs[i] = *s.jobs[id]
+		jobs[i].Result = nil
+		jobs[i].ByRaidCount = nil
+	}
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, jobs)
+}
+
+// GET /jobs/{id} returns the progress of the job, and its result once finished
+func (s *JobServer) get(w http.ResponseWriter, id string) {
+	job, found := s.snapshot(id)
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown job: %s", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// DELETE /jobs/{id} cancels the job. A running job stops at the end of the current generation and keeps the best
+// split found so far.
+func (s *JobServer) cancel(w http.ResponseWriter, id string) {
+	s.mutex.Lock()
+	job, found := s.jobs[id]
+	if found && job.Status == JobQueued {
+		job.Status = JobCancelled
+	}
+	s.mutex.Unlock()
+
+	if !found {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown job: %s", id))
+		return
+	}
+
+	job.cancel()
+	snapshot, _ := s.snapshot(id)
+	writeJSON(w, http.StatusOK, snapshot)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// Serve runs the HTTP server, with its own set of flags
+func Serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	listen := flags.String("listen", "localhost:8080", "address to listen on")
+	workers := flags.Int("workers", 1, "number of jobs running concurrently")
+	flags.Parse(args)
+
+	if *workers < 1 {
+		log.Fatalf("Invalid number of workers: %d", *workers)
+	}
+
+	log.Printf("Listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, NewJobServer(*workers)))
+}